@@ -21,6 +21,7 @@ import (
 	"flag"
 	"log/slog"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -37,11 +38,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	corev1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	computeController "github.com/stateless-pg/stateless-pg/pkg/compute"
 	controlplaneserver "github.com/stateless-pg/stateless-pg/pkg/control-plane"
 	neonclusterController "github.com/stateless-pg/stateless-pg/pkg/neoncluster"
 	pageserverController "github.com/stateless-pg/stateless-pg/pkg/pageserver"
+	profileController "github.com/stateless-pg/stateless-pg/pkg/profile"
 	safekeeperController "github.com/stateless-pg/stateless-pg/pkg/safekeeper"
 	storagebrokerController "github.com/stateless-pg/stateless-pg/pkg/storagebroker"
+	tenantController "github.com/stateless-pg/stateless-pg/pkg/tenant"
+	timelineController "github.com/stateless-pg/stateless-pg/pkg/timeline"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -56,6 +61,8 @@ var (
 	enableHTTP2                                      bool
 	controlPlaneEnableTLS                            bool
 	controlPlaneEnableJWT                            bool
+	controlPlaneBootstrap                            bool
+	controlPlaneJWTTTL                               time.Duration
 )
 
 func init() {
@@ -87,6 +94,10 @@ func parseFlags(fs *flag.FlagSet) {
 		"If set, TLS will be enabled for the control plane server")
 	fs.BoolVar(&controlPlaneEnableJWT, "control-plane-enable-jwt", false,
 		"If set, JWT authentication will be enabled for the control plane server")
+	fs.BoolVar(&controlPlaneBootstrap, "control-plane-bootstrap", true,
+		"If set, the certificate and the JWT keys of the control plane server are generated into their Secrets when missing")
+	fs.DurationVar(&controlPlaneJWTTTL, "control-plane-jwt-ttl", controlplaneserver.DefaultTokenTTL,
+		"The lifetime of the tokens issued to the components, they are refreshed in the last third of it")
 	// No need to check for errors because Parse would exit on error.
 	_ = fs.Parse(os.Args[1:])
 }
@@ -206,6 +217,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	pro := profileController.New(mgr.GetClient(), mgr.GetScheme(), logger)
+	if err := pro.SetupWithManager(mgr); err != nil {
+		logger.Error("unable to create controller", "error", err, "controller", "Profile")
+		os.Exit(1)
+	}
+
 	pso, err := pageserverController.New(mgr.GetClient(), mgr.GetScheme(), logger, mgr.GetConfig())
 	if err != nil {
 		logger.Error("unable to create controller", "error", err, "controller", "PageServer")
@@ -238,6 +255,39 @@ func main() {
 		logger.Error("unable to create controller", "error", err, "controller", "StorageBroker")
 		os.Exit(1)
 	}
+
+	tno, err := tenantController.New(mgr.GetClient(), mgr.GetScheme(), logger, mgr.GetConfig())
+	if err != nil {
+		logger.Error("unable to create controller", "error", err, "controller", "Tenant")
+		os.Exit(1)
+	}
+
+	if err := tno.SetupWithManager(mgr); err != nil {
+		logger.Error("unable to create controller", "error", err, "controller", "Tenant")
+		os.Exit(1)
+	}
+
+	tlo, err := timelineController.New(mgr.GetClient(), mgr.GetScheme(), logger, mgr.GetConfig())
+	if err != nil {
+		logger.Error("unable to create controller", "error", err, "controller", "Timeline")
+		os.Exit(1)
+	}
+
+	if err := tlo.SetupWithManager(mgr); err != nil {
+		logger.Error("unable to create controller", "error", err, "controller", "Timeline")
+		os.Exit(1)
+	}
+
+	ceo, err := computeController.New(mgr.GetClient(), mgr.GetScheme(), logger, mgr.GetConfig())
+	if err != nil {
+		logger.Error("unable to create controller", "error", err, "controller", "ComputeEndpoint")
+		os.Exit(1)
+	}
+
+	if err := ceo.SetupWithManager(mgr); err != nil {
+		logger.Error("unable to create controller", "error", err, "controller", "ComputeEndpoint")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -251,12 +301,22 @@ func main() {
 
 	var cpServer *controlplaneserver.ControlPlaneServer
 	var cpServerErr error
-	cpServer, cpServerErr = controlplaneserver.NewControlPlaneServer(controlPlaneEnableTLS, controlPlaneEnableJWT, logger, mgr.GetClient(), mgr.GetConfig(), mgr.GetScheme())
+	cpServer, cpServerErr = controlplaneserver.NewControlPlaneServer(controlPlaneEnableTLS, controlPlaneEnableJWT, controlPlaneBootstrap, controlPlaneJWTTTL, logger, mgr.GetClient(), mgr.GetConfig(), mgr.GetScheme())
 	if cpServerErr != nil {
 		logger.Error("unable to create control plane server", "error", cpServerErr)
 		os.Exit(1)
 	}
 
+	tokenRefresher, err := controlplaneserver.NewTokenRefresher(mgr.GetClient(), mgr.GetConfig(), logger)
+	if err != nil {
+		logger.Error("unable to create token refresher", "error", err)
+		os.Exit(1)
+	}
+	if err := mgr.Add(tokenRefresher); err != nil {
+		logger.Error("unable to add token refresher", "error", err)
+		os.Exit(1)
+	}
+
 	go func() {
 		if err := cpServer.Start(); err != nil {
 			logger.Error("control plane server error", "error", err)
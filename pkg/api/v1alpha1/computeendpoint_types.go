@@ -0,0 +1,174 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ComputeEndpointKind = "ComputeEndpoint"
+	ComputeEndpointKey  = "computeendpoint"
+	ComputeEndpointName = "computeendpoints"
+
+	// ComputeEndpointConditionReady indicates whether the compute is running and accepting connections
+	ComputeEndpointConditionReady = "Ready"
+	// ComputeEndpointConditionConfigured indicates whether the running compute got the latest attachment of its tenant
+	ComputeEndpointConditionConfigured = "Configured"
+)
+
+// ComputeRole defines a Postgres role created by compute_ctl.
+type ComputeRole struct {
+	// name is the name of the role
+	// +required
+	Name string `json:"name"`
+
+	// passwordSecretRef selects the key of a Secret, in the same namespace, holding the password of the role.
+	// The role cannot log in with a password when it is not set.
+	// +optional
+	PasswordSecretRef *v1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+}
+
+// ComputeDatabase defines a Postgres database created by compute_ctl.
+type ComputeDatabase struct {
+	// name is the name of the database
+	// +required
+	Name string `json:"name"`
+
+	// owner is the role owning the database
+	// +required
+	Owner string `json:"owner"`
+}
+
+// ComputeEndpointSpec defines the desired state of ComputeEndpoint.
+// +k8s:openapi-gen=true
+type ComputeEndpointSpec struct {
+	CommonFields `json:",inline"`
+
+	// timelineRef is a reference to the Timeline, in the same namespace, the compute runs on
+	// +required
+	TimelineRef v1.LocalObjectReference `json:"timelineRef"`
+
+	// roles defines the Postgres roles of the compute
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Roles []ComputeRole `json:"roles,omitempty"`
+
+	// databases defines the Postgres databases of the compute
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Databases []ComputeDatabase `json:"databases,omitempty"`
+
+	// postgresSettings defines additional postgresql.conf settings
+	// +optional
+	//nolint:kubeapilinter
+	PostgresSettings map[string]string `json:"postgresSettings,omitempty"`
+
+	// serviceType defines the type of the Service exposing Postgres
+	// +kubebuilder:default=ClusterIP
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	ServiceType v1.ServiceType `json:"serviceType,omitempty"`
+}
+
+// ComputeEndpointStatus defines the observed state of ComputeEndpoint.
+// +k8s:openapi-gen=true
+type ComputeEndpointStatus struct {
+	// observedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// tenantId is the ID of the tenant the compute runs on
+	// +optional
+	TenantID string `json:"tenantId,omitempty"`
+
+	// timelineId is the ID of the timeline the compute runs on
+	// +optional
+	TimelineID string `json:"timelineId,omitempty"`
+
+	// serviceName is the name of the Service exposing Postgres
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+
+	// readyReplicas is the number of ready compute pods
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// configuredPageServerConnstring is the pageserver connection string last pushed to the running compute
+	// +optional
+	ConfiguredPageServerConnstring string `json:"configuredPageServerConnstring,omitempty"`
+
+	// lastConfiguredTime is the last time a spec was successfully pushed to the running compute
+	// +optional
+	LastConfiguredTime *metav1.Time `json:"lastConfiguredTime,omitempty"`
+
+	// conditions represent the current state of the ComputeEndpoint resource.
+	//
+	// Standard condition types include:
+	// - "Ready": the compute is running and accepting connections
+	// - "Configured": the running compute got the latest attachment of its tenant
+	//
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories="stateless-pg",shortName="ep"
+// +kubebuilder:printcolumn:name="Timeline",type="string",JSONPath=".spec.timelineRef.name"
+// +kubebuilder:printcolumn:name="Service",type="string",JSONPath=".status.serviceName"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type == 'Ready')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+
+// ComputeEndpoint is the Schema for the computeendpoints API.
+// It runs a Postgres compute on a timeline.
+type ComputeEndpoint struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of ComputeEndpoint
+	// +required
+	Spec ComputeEndpointSpec `json:"spec"`
+
+	// status defines the observed state of ComputeEndpoint
+	// +optional
+	Status ComputeEndpointStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ComputeEndpointList contains a list of ComputeEndpoint
+// +k8s:openapi-gen=true
+type ComputeEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ComputeEndpoint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ComputeEndpoint{}, &ComputeEndpointList{})
+}
@@ -19,16 +19,66 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
 	NeonClusterKind = "NeonCluster"
 	NeonClusterKey  = "neoncluster"
 	NeonClusterName = "neonclusters"
+
+	// NeonClusterConditionAvailable indicates whether the cluster can serve computes:
+	// every pageserver is ready, the safekeepers have a quorum and the broker is running
+	NeonClusterConditionAvailable = "Available"
+	// NeonClusterConditionProgressing indicates whether a component is being created or rolled out.
+	// Components roll out in order, the reason names the current stage: StorageBrokerRollingOut,
+	// SafeKeepersRollingOut or PageServersRollingOut
+	NeonClusterConditionProgressing = "Progressing"
+	// NeonClusterConditionDegraded indicates whether the last reconcile failed or a rolled out component lost replicas
+	NeonClusterConditionDegraded = "Degraded"
+)
+
+// DeletionPolicy decides what happens to the data of a NeonCluster in object storage when it is deleted.
+// +kubebuilder:validation:Enum=Retain;Delete
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyRetain keeps the objects under the bucket prefix, tenants can be attached again
+	// to a NeonCluster with the same name and object storage
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+	// DeletionPolicyDelete purges the bucket prefix once the components are shut down
+	DeletionPolicyDelete DeletionPolicy = "Delete"
 )
 
+// NeonClusterComponentSpec adjusts the profile of a NeonCluster component for this cluster only.
+// The fields are applied on top of the referenced profile, profileOverride first.
+type NeonClusterComponentSpec struct {
+	// replicas overrides minReplicas of the profile, the number of pods
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// image overrides the container image of the profile
+	// +optional
+	Image *string `json:"image,omitempty"`
+
+	// resources overrides the resource requests and limits of the profile
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// profileOverride is a partial profile spec deep merged on top of the spec of the referenced profile.
+	// Objects are merged key by key, lists and values replace the profile ones and null removes them.
+	// The result must be a valid profile spec.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +optional
+	ProfileOverride *runtime.RawExtension `json:"profileOverride,omitempty"`
+}
+
 // NeonClusterSpec defines the desired state of NeonCluster.
 // +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="!has(self.deletionPolicy) || self.deletionPolicy != 'Delete' || (has(self.objectStorage.prefix) && size(self.objectStorage.prefix) > 0)",message="deletionPolicy Delete requires objectStorage.prefix"
 type NeonClusterSpec struct {
 	// regionName is the name of the region where the NeonCluster is deployed
 	// +optional
@@ -48,6 +98,47 @@ type NeonClusterSpec struct {
 	// objectStorage defines the configuration for object storage used by Neon components
 	// +required
 	ObjectStorage ObjectStorageSpec `json:"objectStorage"`
+
+	// pageServer adjusts the pageserver profile for this cluster
+	// +optional
+	PageServer *NeonClusterComponentSpec `json:"pageServer,omitempty"`
+
+	// safeKeeper adjusts the safekeeper profile for this cluster
+	// +optional
+	SafeKeeper *NeonClusterComponentSpec `json:"safeKeeper,omitempty"`
+
+	// storageBroker adjusts the storage broker profile for this cluster
+	// +optional
+	StorageBroker *NeonClusterComponentSpec `json:"storageBroker,omitempty"`
+
+	// deletionPolicy decides whether the objectStorage prefix is purged when the NeonCluster is deleted.
+	// Delete requires a prefix, the bucket itself is never purged.
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// NeonClusterComponentStatus summarizes the workload of a NeonCluster component.
+type NeonClusterComponentStatus struct {
+	// name is the name of the child resource of the component
+	// +required
+	Name string `json:"name"`
+
+	// replicas is the desired number of pods
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// readyReplicas is the number of ready pods
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// updatedReplicas is the number of pods running the latest revision
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// ready is the number of ready pods out of the desired number, e.g. "2/3"
+	// +optional
+	Ready string `json:"ready,omitempty"`
 }
 
 // NeonClusterStatus defines the observed state of NeonCluster.
@@ -56,6 +147,22 @@ type NeonClusterStatus struct {
 	// For Kubernetes API conventions, see:
 	// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
 
+	// observedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// pageServer summarizes the pageserver pods
+	// +optional
+	PageServer *NeonClusterComponentStatus `json:"pageServer,omitempty"`
+
+	// safeKeeper summarizes the safekeeper pods
+	// +optional
+	SafeKeeper *NeonClusterComponentStatus `json:"safeKeeper,omitempty"`
+
+	// storageBroker summarizes the storage broker pods
+	// +optional
+	StorageBroker *NeonClusterComponentStatus `json:"storageBroker,omitempty"`
+
 	// conditions represent the current state of the NeonCluster resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
@@ -64,6 +171,8 @@ type NeonClusterStatus struct {
 	// - "Progressing": the resource is being created or updated
 	// - "Degraded": the resource failed to reach or maintain its desired state
 	//
+	// While the NeonCluster is deleted, Progressing reports the current teardown step.
+	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
 	// +listMapKey=type
@@ -75,7 +184,11 @@ type NeonClusterStatus struct {
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories="stateless-pg",shortName="nc"
+// +kubebuilder:printcolumn:name="PageServers",type="string",JSONPath=".status.pageServer.ready"
+// +kubebuilder:printcolumn:name="SafeKeepers",type="string",JSONPath=".status.safeKeeper.ready"
+// +kubebuilder:printcolumn:name="Broker",type="string",JSONPath=".status.storageBroker.ready"
 // +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type == 'Available')].status"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type == 'Available')].reason",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 
@@ -53,16 +53,43 @@ type PageServerSpec struct {
 	JwtPublicKeySecretRef *v1.SecretReference `json:"jwtPublicKeySecretRef,omitempty"`
 }
 
+// PageServerNode is a pageserver pod registered with the control plane.
+type PageServerNode struct {
+	// pod is the name of the pageserver pod
+	// +required
+	Pod string `json:"pod"`
+
+	// nodeId is the stable numeric node ID of the pageserver
+	// +required
+	NodeID int64 `json:"nodeId"`
+
+	// listenPgAddr is the address of the libpq page service
+	// +optional
+	ListenPgAddr string `json:"listenPgAddr,omitempty"`
+
+	// listenHttpAddr is the address of the management API
+	// +optional
+	ListenHTTPAddr string `json:"listenHttpAddr,omitempty"`
+}
+
 // PageServerStatus defines the observed state of PageServer.
 // +k8s:openapi-gen=true
 type PageServerStatus struct {
+	WorkloadStatus `json:",inline"`
+
+	// nodes lists the pageserver pods and their node IDs, ordered by StatefulSet ordinal
+	// +listType=map
+	// +listMapKey=pod
+	// +optional
+	Nodes []PageServerNode `json:"nodes,omitempty"`
+
 	// conditions represent the current state of the PageServer resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
 	// Standard condition types include:
-	// - "Available": the resource is fully functional
+	// - "Ready": every pod runs the latest spec and is ready
 	// - "Progressing": the resource is being created or updated
-	// - "Degraded": the resource failed to reach or maintain its desired state
+	// - "Degraded": the last reconcile failed or a rolled out pod is not ready
 	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
@@ -75,7 +102,9 @@ type PageServerStatus struct {
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories="stateless-pg",shortName="ps"
-// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type == 'Available')].status"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="Ready Replicas",type="integer",JSONPath=".status.readyReplicas"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type == 'Ready')].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 
@@ -28,13 +28,46 @@ const (
 	PageServerProfileName = "pageserverprofiles"
 )
 
+// PageServerMode selects the defaults and guardrails applied to a PageServer
+type PageServerMode string
+
+const (
+	// PageServerModeDev trades durability and security for a minimal footprint
+	PageServerModeDev PageServerMode = "dev"
+	// PageServerModeProduction enforces persistent storage, authentication and high availability
+	PageServerModeProduction PageServerMode = "production"
+
+	// PageServerModeStandalone is accepted for profiles created before the dev and production modes.
+	// Deprecated: it runs in production mode like every mode other than dev.
+	PageServerModeStandalone PageServerMode = "standalone"
+	// PageServerModeManaged is the former default mode, accepted for profiles created before the dev and production modes.
+	// Deprecated: it runs in production mode like every mode other than dev.
+	PageServerModeManaged PageServerMode = "managed"
+)
+
 // PageServerProfileSpec defines the desired state of PageServerProfile.
 // +k8s:openapi-gen=true
 type PageServerProfileSpec struct {
-	// mode defines whether PageServer runs standalone or with a control plane.
-	// +kubebuilder:validation:Enum=standalone;managed
-	// +kubebuilder:default=managed
-	Mode string `json:"mode,omitempty"`
+	// baseProfileRef references a PageServerProfile this profile inherits from, the namespace defaults to the one of this profile.
+	// The fields set in this profile are deep merged on top of the resolved base profile, the others are inherited.
+	// A field is set when it was applied (server-side or kubectl apply) or differs from its default.
+	// status.resolved shows the result.
+	// +optional
+	BaseProfileRef *v1.ObjectReference `json:"baseProfileRef,omitempty"`
+
+	// rollout stages the changes of this profile cluster by cluster.
+	// Without it every component using the profile applies a change at once.
+	// +optional
+	Rollout *ProfileRolloutSpec `json:"rollout,omitempty"`
+
+	// mode selects the defaults and guardrails applied to the PageServer.
+	// dev runs a single pod on an emptyDir without auth nor fsync.
+	// production requires a volumeClaimTemplate, JWT auth and bounded durability settings,
+	// and spreads the pods over nodes behind a PodDisruptionBudget.
+	// The deprecated standalone and managed values of older profiles run in production mode.
+	// +kubebuilder:validation:Enum=dev;production;standalone;managed
+	// +kubebuilder:default=production
+	Mode PageServerMode `json:"mode,omitempty"`
 
 	// controlPlane configures controller connectivity.
 	// +optional
@@ -146,7 +179,7 @@ type SecuritySpec struct {
 
 	// authType controls API auth.
 	// +kubebuilder:validation:Enum=Trust;NeonJWT
-	// +kubebuilder:default=jwt
+	// +kubebuilder:default=NeonJWT
 	AuthType string `json:"authType,omitempty"`
 }
 
@@ -161,10 +194,22 @@ type ObservabilitySpec struct {
 	Metrics bool `json:"metrics,omitempty"`
 }
 
+// PageServerProfileStatus defines the observed state of PageServerProfile.
+type PageServerProfileStatus struct {
+	ProfileStatus `json:",inline"`
+
+	// resolved is the effective spec of the profile, its inheritance chain deep merged
+	// +optional
+	Resolved *PageServerProfileSpec `json:"resolved,omitempty"`
+}
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories="stateless-pg",shortName="psp"
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Base",type="string",JSONPath=".spec.baseProfileRef.name"
+// +kubebuilder:printcolumn:name="Resolved",type="string",JSONPath=".status.conditions[?(@.type==\"Resolved\")].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // PageServerProfile is the Schema for the pageserverprofiles API
@@ -178,6 +223,10 @@ type PageServerProfile struct {
 	// spec defines the desired state of PageServerProfile
 	// +required
 	Spec PageServerProfileSpec `json:"spec"`
+
+	// status defines the observed state of PageServerProfile
+	// +optional
+	Status PageServerProfileStatus `json:"status,omitzero"`
 }
 
 // +kubebuilder:object:root=true
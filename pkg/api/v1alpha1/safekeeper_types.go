@@ -53,16 +53,43 @@ type SafeKeeperSpec struct {
 	JwtPublicKeySecretRef *v1.SecretReference `json:"jwtPublicKeySecretRef,omitempty"`
 }
 
+// SafeKeeperMember is a safekeeper pod of the SafeKeeper quorum.
+type SafeKeeperMember struct {
+	// pod is the name of the safekeeper pod
+	// +required
+	Pod string `json:"pod"`
+
+	// nodeId is the node ID of the safekeeper, derived from the StatefulSet ordinal of the pod
+	// +required
+	NodeID int64 `json:"nodeId"`
+
+	// pgAddr is the address computes stream WAL to
+	// +optional
+	PgAddr string `json:"pgAddr,omitempty"`
+
+	// httpAddr is the address of the safekeeper HTTP API
+	// +optional
+	HTTPAddr string `json:"httpAddr,omitempty"`
+}
+
 // SafeKeeperStatus defines the observed state of SafeKeeper.
 // +k8s:openapi-gen=true
 type SafeKeeperStatus struct {
+	WorkloadStatus `json:",inline"`
+
+	// members lists the safekeeper pods and their node IDs, ordered by StatefulSet ordinal
+	// +listType=map
+	// +listMapKey=pod
+	// +optional
+	Members []SafeKeeperMember `json:"members,omitempty"`
+
 	// conditions represent the current state of the SafeKeeper resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
 	// Standard condition types include:
-	// - "Available": the resource is fully functional
+	// - "Ready": every pod runs the latest spec and is ready
 	// - "Progressing": the resource is being created or updated
-	// - "Degraded": the resource failed to reach or maintain its desired state
+	// - "Degraded": the last reconcile failed or a rolled out pod is not ready
 	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
@@ -75,7 +102,9 @@ type SafeKeeperStatus struct {
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories="stateless-pg",shortName="sk"
-// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type == 'Available')].status"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="Ready Replicas",type="integer",JSONPath=".status.readyReplicas"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type == 'Ready')].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 
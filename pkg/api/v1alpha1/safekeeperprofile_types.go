@@ -240,6 +240,18 @@ const (
 // SafeKeeperProfileSpec defines the desired state of SafeKeeperProfile.
 // +k8s:openapi-gen=true
 type SafeKeeperProfileSpec struct {
+	// baseProfileRef references a SafeKeeperProfile this profile inherits from, the namespace defaults to the one of this profile.
+	// The fields set in this profile are deep merged on top of the resolved base profile, the others are inherited.
+	// A field is set when it was applied (server-side or kubectl apply) or differs from its default.
+	// status.resolved shows the result.
+	// +optional
+	BaseProfileRef *v1.ObjectReference `json:"baseProfileRef,omitempty"`
+
+	// rollout stages the changes of this profile cluster by cluster.
+	// Without it every component using the profile applies a change at once.
+	// +optional
+	Rollout *ProfileRolloutSpec `json:"rollout,omitempty"`
+
 	CommonFields `json:",inline"`
 
 	// +kubebuilder:default=3
@@ -281,10 +293,22 @@ type SafeKeeperProfileSpec struct {
 	SafeKeeperConfigOptions `json:",inline"`
 }
 
+// SafeKeeperProfileStatus defines the observed state of SafeKeeperProfile.
+type SafeKeeperProfileStatus struct {
+	ProfileStatus `json:",inline"`
+
+	// resolved is the effective spec of the profile, its inheritance chain deep merged
+	// +optional
+	Resolved *SafeKeeperProfileSpec `json:"resolved,omitempty"`
+}
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories="stateless-pg",shortName="skp"
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Base",type="string",JSONPath=".spec.baseProfileRef.name"
+// +kubebuilder:printcolumn:name="Resolved",type="string",JSONPath=".status.conditions[?(@.type==\"Resolved\")].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // SafeKeeperProfile is the Schema for the safekeeperprofiles API
@@ -298,6 +322,10 @@ type SafeKeeperProfile struct {
 	// spec defines the desired state of SafeKeeperProfile
 	// +required
 	Spec SafeKeeperProfileSpec `json:"spec"`
+
+	// status defines the observed state of SafeKeeperProfile
+	// +optional
+	Status SafeKeeperProfileStatus `json:"status,omitzero"`
 }
 
 // +kubebuilder:object:root=true
@@ -48,13 +48,15 @@ type StorageBrokerSpec struct {
 // StorageBrokerStatus defines the observed state of StorageBroker.
 // +k8s:openapi-gen=true
 type StorageBrokerStatus struct {
+	WorkloadStatus `json:",inline"`
+
 	// conditions represent the current state of the StorageBroker resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
 	// Standard condition types include:
-	// - "Available": the resource is fully functional
+	// - "Ready": every pod runs the latest spec and is ready
 	// - "Progressing": the resource is being created or updated
-	// - "Degraded": the resource failed to reach or maintain its desired state
+	// - "Degraded": the last reconcile failed or a rolled out pod is not ready
 	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
@@ -67,7 +69,9 @@ type StorageBrokerStatus struct {
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories="stateless-pg",shortName="sb"
-// +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type == 'Available')].status"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas"
+// +kubebuilder:printcolumn:name="Ready Replicas",type="integer",JSONPath=".status.readyReplicas"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type == 'Ready')].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 
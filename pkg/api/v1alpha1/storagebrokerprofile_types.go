@@ -71,6 +71,18 @@ type StorageBrokerConfigOptions struct {
 // It will be deployed as a Deployment.
 // +k8s:openapi-gen=true
 type StorageBrokerProfileSpec struct {
+	// baseProfileRef references a StorageBrokerProfile this profile inherits from, the namespace defaults to the one of this profile.
+	// The fields set in this profile are deep merged on top of the resolved base profile, the others are inherited.
+	// A field is set when it was applied (server-side or kubectl apply) or differs from its default.
+	// status.resolved shows the result.
+	// +optional
+	BaseProfileRef *v1.ObjectReference `json:"baseProfileRef,omitempty"`
+
+	// rollout stages the changes of this profile cluster by cluster.
+	// Without it every component using the profile applies a change at once.
+	// +optional
+	Rollout *ProfileRolloutSpec `json:"rollout,omitempty"`
+
 	CommonFields `json:",inline"`
 
 	// +kubebuilder:default=1
@@ -86,10 +98,22 @@ type StorageBrokerProfileSpec struct {
 	StorageBrokerConfigOptions `json:"inline"`
 }
 
+// StorageBrokerProfileStatus defines the observed state of StorageBrokerProfile.
+type StorageBrokerProfileStatus struct {
+	ProfileStatus `json:",inline"`
+
+	// resolved is the effective spec of the profile, its inheritance chain deep merged
+	// +optional
+	Resolved *StorageBrokerProfileSpec `json:"resolved,omitempty"`
+}
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories="stateless-pg",shortName="sbp"
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Base",type="string",JSONPath=".spec.baseProfileRef.name"
+// +kubebuilder:printcolumn:name="Resolved",type="string",JSONPath=".status.conditions[?(@.type==\"Resolved\")].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // StorageBrokerProfile is the Schema for the storagebrokerprofiles API
@@ -103,6 +127,10 @@ type StorageBrokerProfile struct {
 	// spec defines the desired state of StorageBrokerProfile
 	// +required
 	Spec StorageBrokerProfileSpec `json:"spec"`
+
+	// status defines the observed state of StorageBrokerProfile
+	// +optional
+	Status StorageBrokerProfileStatus `json:"status,omitzero"`
 }
 
 // +kubebuilder:object:root=true
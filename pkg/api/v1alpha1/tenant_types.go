@@ -0,0 +1,175 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	TenantKind = "Tenant"
+	TenantKey  = "tenant"
+	TenantName = "tenants"
+
+	// TenantConditionReady indicates whether the tenant is attached and configured on a pageserver
+	TenantConditionReady = "Ready"
+)
+
+// TenantConfigSpec defines the tenant level pageserver configuration.
+// Unset fields fall back to the pageserver defaults.
+// +k8s:openapi-gen=true
+type TenantConfigSpec struct {
+	// checkpointDistance is the amount of WAL in bytes kept in memory before it is flushed to a layer file
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CheckpointDistance *int64 `json:"checkpointDistance,omitempty"`
+
+	// checkpointTimeout forces a flush of in-memory WAL after this duration (e.g., 10m)
+	// +optional
+	CheckpointTimeout *string `json:"checkpointTimeout,omitempty"`
+
+	// compactionPeriod is the interval between compaction runs (e.g., 20s)
+	// +optional
+	CompactionPeriod *string `json:"compactionPeriod,omitempty"`
+
+	// gcHorizon is the amount of history in bytes retained by garbage collection
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GCHorizon *int64 `json:"gcHorizon,omitempty"`
+
+	// gcPeriod is the interval between garbage collection runs (e.g., 1h)
+	// +optional
+	GCPeriod *string `json:"gcPeriod,omitempty"`
+
+	// pitrInterval is the point-in-time recovery window (e.g., 7 days)
+	// +optional
+	PITRInterval *string `json:"pitrInterval,omitempty"`
+
+	// imageCreationThreshold is the number of delta layers that triggers image layer creation
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ImageCreationThreshold *int64 `json:"imageCreationThreshold,omitempty"`
+
+	// walReceiverConnectTimeout bounds connection attempts to safekeepers (e.g., 10s)
+	// +optional
+	WalReceiverConnectTimeout *string `json:"walReceiverConnectTimeout,omitempty"`
+
+	// laggingWalTimeout switches safekeeper when no WAL is received for this duration (e.g., 10s)
+	// +optional
+	LaggingWalTimeout *string `json:"laggingWalTimeout,omitempty"`
+
+	// maxLsnWalLag switches safekeeper when another one is ahead by this many bytes
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxLsnWalLag *int64 `json:"maxLsnWalLag,omitempty"`
+}
+
+// TenantSpec defines the desired state of Tenant.
+// +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.tenantId) || size(oldSelf.tenantId) == 0 || self.tenantId == oldSelf.tenantId",message="tenantId is immutable"
+type TenantSpec struct {
+	// neonClusterRef is a reference to the NeonCluster, in the same namespace, hosting the tenant
+	// +required
+	NeonClusterRef v1.LocalObjectReference `json:"neonClusterRef"`
+
+	// tenantId is the 32 character hex ID of the tenant.
+	// A random ID is generated when it is not set.
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{32}$`
+	// +optional
+	TenantID string `json:"tenantId,omitempty"`
+
+	// config defines the tenant level pageserver configuration
+	// +optional
+	Config TenantConfigSpec `json:"config,omitempty"`
+}
+
+// TenantStatus defines the observed state of Tenant.
+// +k8s:openapi-gen=true
+type TenantStatus struct {
+	// observedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// tenantId is the ID of the tenant on the pageserver
+	// +optional
+	TenantID string `json:"tenantId,omitempty"`
+
+	// pageServerPod is the name of the pageserver pod the tenant is attached to
+	// +optional
+	PageServerPod string `json:"pageServerPod,omitempty"`
+
+	// pageServerNodeId is the node ID of the pageserver the tenant is attached to
+	// +optional
+	PageServerNodeID int64 `json:"pageServerNodeId,omitempty"`
+
+	// attachGeneration is the generation number of the current attachment
+	// +optional
+	AttachGeneration int64 `json:"attachGeneration,omitempty"`
+
+	// conditions represent the current state of the Tenant resource.
+	//
+	// Standard condition types include:
+	// - "Ready": the tenant is attached and configured on a pageserver
+	//
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories="stateless-pg",shortName="tn"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.neonClusterRef.name"
+// +kubebuilder:printcolumn:name="Tenant ID",type="string",JSONPath=".status.tenantId"
+// +kubebuilder:printcolumn:name="PageServer",type="string",JSONPath=".status.pageServerPod"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type == 'Ready')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+
+// Tenant is the Schema for the tenants API
+type Tenant struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of Tenant
+	// +required
+	Spec TenantSpec `json:"spec"`
+
+	// status defines the observed state of Tenant
+	// +optional
+	Status TenantStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// TenantList contains a list of Tenant
+// +k8s:openapi-gen=true
+type TenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []Tenant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Tenant{}, &TenantList{})
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	TimelineKind = "Timeline"
+	TimelineKey  = "timeline"
+	TimelineName = "timelines"
+
+	// TimelineConditionReady indicates whether the timeline exists on the pageserver
+	TimelineConditionReady = "Ready"
+)
+
+// TimelineSpec defines the desired state of Timeline.
+// A timeline without an ancestor is bootstrapped from an empty database,
+// otherwise it is branched from the ancestor at ancestorLsn, ancestorTimestamp or its latest LSN.
+// +k8s:openapi-gen=true
+// +kubebuilder:validation:XValidation:rule="!(has(self.ancestorLsn) && has(self.ancestorTimestamp))",message="ancestorLsn and ancestorTimestamp are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="has(self.ancestorTimelineRef) || (!has(self.ancestorLsn) && !has(self.ancestorTimestamp))",message="ancestorLsn and ancestorTimestamp require ancestorTimelineRef"
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.timelineId) || size(oldSelf.timelineId) == 0 || self.timelineId == oldSelf.timelineId",message="timelineId is immutable"
+type TimelineSpec struct {
+	// tenantRef is a reference to the Tenant, in the same namespace, owning the timeline
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tenantRef is immutable"
+	// +required
+	TenantRef v1.LocalObjectReference `json:"tenantRef"`
+
+	// timelineId is the 32 character hex ID of the timeline.
+	// A random ID is generated when it is not set.
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{32}$`
+	// +optional
+	TimelineID string `json:"timelineId,omitempty"`
+
+	// ancestorTimelineRef is a reference to the Timeline, of the same tenant, to branch from
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ancestorTimelineRef is immutable"
+	// +optional
+	AncestorTimelineRef *v1.LocalObjectReference `json:"ancestorTimelineRef,omitempty"`
+
+	// ancestorLsn is the LSN of the ancestor timeline to branch at (e.g., 0/16B5A50)
+	// +kubebuilder:validation:Pattern=`^[0-9A-F]{1,8}/[0-9A-F]{1,8}$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ancestorLsn is immutable"
+	// +optional
+	AncestorLSN *string `json:"ancestorLsn,omitempty"`
+
+	// ancestorTimestamp is the point in time of the ancestor timeline to branch at
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ancestorTimestamp is immutable"
+	// +optional
+	AncestorTimestamp *metav1.Time `json:"ancestorTimestamp,omitempty"`
+
+	// pgVersion is the major Postgres version of a bootstrapped timeline.
+	// Branches always inherit the version of their ancestor.
+	// +kubebuilder:validation:Minimum=14
+	// +kubebuilder:validation:Maximum=17
+	// +optional
+	PGVersion *int32 `json:"pgVersion,omitempty"`
+}
+
+// TimelineStatus defines the observed state of Timeline.
+// +k8s:openapi-gen=true
+type TimelineStatus struct {
+	// observedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// tenantId is the ID of the tenant owning the timeline
+	// +optional
+	TenantID string `json:"tenantId,omitempty"`
+
+	// timelineId is the ID of the timeline on the pageserver
+	// +optional
+	TimelineID string `json:"timelineId,omitempty"`
+
+	// ancestorTimelineId is the ID of the timeline this timeline was branched from
+	// +optional
+	AncestorTimelineID string `json:"ancestorTimelineId,omitempty"`
+
+	// ancestorLsn is the LSN of the ancestor timeline this timeline was branched at
+	// +optional
+	AncestorLSN string `json:"ancestorLsn,omitempty"`
+
+	// pgVersion is the major Postgres version of the timeline
+	// +optional
+	PGVersion int32 `json:"pgVersion,omitempty"`
+
+	// lastRecordLsn is the LSN of the last WAL record ingested by the pageserver
+	// +optional
+	LastRecordLSN string `json:"lastRecordLsn,omitempty"`
+
+	// currentLogicalSize is the logical size of the timeline in bytes
+	// +optional
+	CurrentLogicalSize int64 `json:"currentLogicalSize,omitempty"`
+
+	// conditions represent the current state of the Timeline resource.
+	//
+	// Standard condition types include:
+	// - "Ready": the timeline exists on the pageserver
+	//
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories="stateless-pg",shortName={tl,branch}
+// +kubebuilder:printcolumn:name="Tenant",type="string",JSONPath=".spec.tenantRef.name"
+// +kubebuilder:printcolumn:name="Timeline ID",type="string",JSONPath=".status.timelineId"
+// +kubebuilder:printcolumn:name="Ancestor",type="string",JSONPath=".spec.ancestorTimelineRef.name"
+// +kubebuilder:printcolumn:name="Last Record LSN",type="string",JSONPath=".status.lastRecordLsn"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type == 'Ready')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+
+// Timeline is the Schema for the timelines API.
+// A timeline is a branch of the database history of a tenant.
+type Timeline struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of Timeline
+	// +required
+	Spec TimelineSpec `json:"spec"`
+
+	// status defines the observed state of Timeline
+	// +optional
+	Status TimelineStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// TimelineList contains a list of Timeline
+// +k8s:openapi-gen=true
+type TimelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []Timeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Timeline{}, &TimelineList{})
+}
@@ -95,6 +95,116 @@ type StorageSpec struct {
 	VolumeClaimTemplate EmbeddedPersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
 }
 
+const (
+	// ComponentConditionReady indicates whether every pod of a component runs and is ready
+	ComponentConditionReady = "Ready"
+	// ComponentConditionProgressing indicates whether a component is being created or rolled out
+	ComponentConditionProgressing = "Progressing"
+	// ComponentConditionDegraded indicates whether the last reconcile failed or a rolled out component lost pods
+	ComponentConditionDegraded = "Degraded"
+)
+
+const (
+	// ProfileConditionResolved indicates whether the inheritance chain of a profile could be resolved
+	ProfileConditionResolved = "Resolved"
+	// ProfileConditionRolloutPaused indicates whether the rollout of a profile change stopped on a failing cluster
+	ProfileConditionRolloutPaused = "RolloutPaused"
+)
+
+// ProfileRolloutSpec controls how a change of a profile rolls out to the clusters using it.
+// +k8s:openapi-gen=true
+type ProfileRolloutSpec struct {
+	// maxUnavailableClusters is the number of clusters allowed to be rolling out the change or unavailable at the same time
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxUnavailableClusters int32 `json:"maxUnavailableClusters,omitempty"`
+
+	// canarySelector selects the NeonClusters rolling out a change first.
+	// The other clusters wait until every canary cluster runs the change and is ready.
+	// +optional
+	CanarySelector *metav1.LabelSelector `json:"canarySelector,omitempty"`
+
+	// pauseOnFailure stops admitting clusters while a cluster running the change is degraded
+	// +kubebuilder:default=true
+	// +optional
+	PauseOnFailure *bool `json:"pauseOnFailure,omitempty"`
+}
+
+// ProfileRolloutStatus is the progress of the rollout of a profile change.
+// +k8s:deepcopy-gen=true
+type ProfileRolloutStatus struct {
+	// hash is the hash of the resolved profile spec being rolled out
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// admitted lists the components, as namespace/name, allowed to apply the change
+	// +optional
+	Admitted []string `json:"admitted,omitempty"`
+
+	// clusters is the number of clusters using the profile
+	// +optional
+	Clusters int32 `json:"clusters,omitempty"`
+
+	// updatedClusters is the number of clusters running the change and ready
+	// +optional
+	UpdatedClusters int32 `json:"updatedClusters,omitempty"`
+}
+
+// ProfileStatus is the observed state of the inheritance chain of a PageServerProfile, SafeKeeperProfile or StorageBrokerProfile.
+// +k8s:deepcopy-gen=true
+type ProfileStatus struct {
+	// observedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// chain lists the profiles merged into the resolved spec as namespace/name, from the root base profile to this profile
+	// +optional
+	Chain []string `json:"chain,omitempty"`
+
+	// rollout is the progress of the rollout of the last profile change, when the profile has a rollout policy
+	// +optional
+	Rollout *ProfileRolloutStatus `json:"rollout,omitempty"`
+
+	// conditions represent the current state of the profile
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// WorkloadStatus is the observed state of the pods of a PageServer, SafeKeeper or StorageBroker.
+// +k8s:deepcopy-gen=true
+type WorkloadStatus struct {
+	// observedGeneration is the most recent generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// replicas is the desired number of pods
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// readyReplicas is the number of ready pods
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// updatedReplicas is the number of pods running the update revision
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// currentRevision is the revision of the pods before the rollout in progress, if any
+	// +optional
+	CurrentRevision string `json:"currentRevision,omitempty"`
+
+	// updateRevision is the revision the pods are rolled out to
+	// +optional
+	UpdateRevision string `json:"updateRevision,omitempty"`
+
+	// profileHash is the hash of the profile spec applied to the pods
+	// +optional
+	ProfileHash string `json:"profileHash,omitempty"`
+}
+
 // +k8s:deepcopy-gen=true
 type CommonFields struct {
 	// kubebuilder:default="ghcr.io/neondatabase/neon:latest"
@@ -24,7 +24,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -70,6 +70,162 @@ func (in *CommonFields) DeepCopy() *CommonFields {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeDatabase) DeepCopyInto(out *ComputeDatabase) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeDatabase.
+func (in *ComputeDatabase) DeepCopy() *ComputeDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeEndpoint) DeepCopyInto(out *ComputeEndpoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeEndpoint.
+func (in *ComputeEndpoint) DeepCopy() *ComputeEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComputeEndpoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeEndpointList) DeepCopyInto(out *ComputeEndpointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ComputeEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeEndpointList.
+func (in *ComputeEndpointList) DeepCopy() *ComputeEndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeEndpointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ComputeEndpointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeEndpointSpec) DeepCopyInto(out *ComputeEndpointSpec) {
+	*out = *in
+	in.CommonFields.DeepCopyInto(&out.CommonFields)
+	out.TimelineRef = in.TimelineRef
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ComputeRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]ComputeDatabase, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostgresSettings != nil {
+		in, out := &in.PostgresSettings, &out.PostgresSettings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeEndpointSpec.
+func (in *ComputeEndpointSpec) DeepCopy() *ComputeEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeEndpointStatus) DeepCopyInto(out *ComputeEndpointStatus) {
+	*out = *in
+	if in.LastConfiguredTime != nil {
+		in, out := &in.LastConfiguredTime, &out.LastConfiguredTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeEndpointStatus.
+func (in *ComputeEndpointStatus) DeepCopy() *ComputeEndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeRole) DeepCopyInto(out *ComputeRole) {
+	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeRole.
+func (in *ComputeRole) DeepCopy() *ComputeRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneSpec) DeepCopyInto(out *ControlPlaneSpec) {
 	*out = *in
@@ -175,6 +331,56 @@ func (in *NeonCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NeonClusterComponentSpec) DeepCopyInto(out *NeonClusterComponentSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProfileOverride != nil {
+		in, out := &in.ProfileOverride, &out.ProfileOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeonClusterComponentSpec.
+func (in *NeonClusterComponentSpec) DeepCopy() *NeonClusterComponentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NeonClusterComponentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NeonClusterComponentStatus) DeepCopyInto(out *NeonClusterComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeonClusterComponentStatus.
+func (in *NeonClusterComponentStatus) DeepCopy() *NeonClusterComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NeonClusterComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NeonClusterList) DeepCopyInto(out *NeonClusterList) {
 	*out = *in
@@ -226,6 +432,21 @@ func (in *NeonClusterSpec) DeepCopyInto(out *NeonClusterSpec) {
 		**out = **in
 	}
 	in.ObjectStorage.DeepCopyInto(&out.ObjectStorage)
+	if in.PageServer != nil {
+		in, out := &in.PageServer, &out.PageServer
+		*out = new(NeonClusterComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SafeKeeper != nil {
+		in, out := &in.SafeKeeper, &out.SafeKeeper
+		*out = new(NeonClusterComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StorageBroker != nil {
+		in, out := &in.StorageBroker, &out.StorageBroker
+		*out = new(NeonClusterComponentSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeonClusterSpec.
@@ -241,6 +462,21 @@ func (in *NeonClusterSpec) DeepCopy() *NeonClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NeonClusterStatus) DeepCopyInto(out *NeonClusterStatus) {
 	*out = *in
+	if in.PageServer != nil {
+		in, out := &in.PageServer, &out.PageServer
+		*out = new(NeonClusterComponentStatus)
+		**out = **in
+	}
+	if in.SafeKeeper != nil {
+		in, out := &in.SafeKeeper, &out.SafeKeeper
+		*out = new(NeonClusterComponentStatus)
+		**out = **in
+	}
+	if in.StorageBroker != nil {
+		in, out := &in.StorageBroker, &out.StorageBroker
+		*out = new(NeonClusterComponentStatus)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -366,12 +602,28 @@ func (in *PageServerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PageServerNode) DeepCopyInto(out *PageServerNode) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PageServerNode.
+func (in *PageServerNode) DeepCopy() *PageServerNode {
+	if in == nil {
+		return nil
+	}
+	out := new(PageServerNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PageServerProfile) DeepCopyInto(out *PageServerProfile) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PageServerProfile.
@@ -427,6 +679,16 @@ func (in *PageServerProfileList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PageServerProfileSpec) DeepCopyInto(out *PageServerProfileSpec) {
 	*out = *in
+	if in.BaseProfileRef != nil {
+		in, out := &in.BaseProfileRef, &out.BaseProfileRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ProfileRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ControlPlane = in.ControlPlane
 	out.Durability = in.Durability
 	out.Retention = in.Retention
@@ -480,6 +742,27 @@ func (in *PageServerProfileSpec) DeepCopy() *PageServerProfileSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PageServerProfileStatus) DeepCopyInto(out *PageServerProfileStatus) {
+	*out = *in
+	in.ProfileStatus.DeepCopyInto(&out.ProfileStatus)
+	if in.Resolved != nil {
+		in, out := &in.Resolved, &out.Resolved
+		*out = new(PageServerProfileSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PageServerProfileStatus.
+func (in *PageServerProfileStatus) DeepCopy() *PageServerProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PageServerProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PageServerSpec) DeepCopyInto(out *PageServerSpec) {
 	*out = *in
@@ -519,6 +802,12 @@ func (in *PageServerSpec) DeepCopy() *PageServerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PageServerStatus) DeepCopyInto(out *PageServerStatus) {
 	*out = *in
+	out.WorkloadStatus = in.WorkloadStatus
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]PageServerNode, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -558,6 +847,83 @@ func (in *PerformanceSpec) DeepCopy() *PerformanceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileRolloutSpec) DeepCopyInto(out *ProfileRolloutSpec) {
+	*out = *in
+	if in.CanarySelector != nil {
+		in, out := &in.CanarySelector, &out.CanarySelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PauseOnFailure != nil {
+		in, out := &in.PauseOnFailure, &out.PauseOnFailure
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileRolloutSpec.
+func (in *ProfileRolloutSpec) DeepCopy() *ProfileRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileRolloutStatus) DeepCopyInto(out *ProfileRolloutStatus) {
+	*out = *in
+	if in.Admitted != nil {
+		in, out := &in.Admitted, &out.Admitted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileRolloutStatus.
+func (in *ProfileRolloutStatus) DeepCopy() *ProfileRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileStatus) DeepCopyInto(out *ProfileStatus) {
+	*out = *in
+	if in.Chain != nil {
+		in, out := &in.Chain, &out.Chain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ProfileRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileStatus.
+func (in *ProfileStatus) DeepCopy() *ProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetentionSpec) DeepCopyInto(out *RetentionSpec) {
 	*out = *in
@@ -677,12 +1043,28 @@ func (in *SafeKeeperList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SafeKeeperMember) DeepCopyInto(out *SafeKeeperMember) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SafeKeeperMember.
+func (in *SafeKeeperMember) DeepCopy() *SafeKeeperMember {
+	if in == nil {
+		return nil
+	}
+	out := new(SafeKeeperMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SafeKeeperProfile) DeepCopyInto(out *SafeKeeperProfile) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SafeKeeperProfile.
@@ -738,6 +1120,16 @@ func (in *SafeKeeperProfileList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SafeKeeperProfileSpec) DeepCopyInto(out *SafeKeeperProfileSpec) {
 	*out = *in
+	if in.BaseProfileRef != nil {
+		in, out := &in.BaseProfileRef, &out.BaseProfileRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ProfileRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.CommonFields.DeepCopyInto(&out.CommonFields)
 	if in.MinReplicas != nil {
 		in, out := &in.MinReplicas, &out.MinReplicas
@@ -786,6 +1178,27 @@ func (in *SafeKeeperProfileSpec) DeepCopy() *SafeKeeperProfileSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SafeKeeperProfileStatus) DeepCopyInto(out *SafeKeeperProfileStatus) {
+	*out = *in
+	in.ProfileStatus.DeepCopyInto(&out.ProfileStatus)
+	if in.Resolved != nil {
+		in, out := &in.Resolved, &out.Resolved
+		*out = new(SafeKeeperProfileSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SafeKeeperProfileStatus.
+func (in *SafeKeeperProfileStatus) DeepCopy() *SafeKeeperProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SafeKeeperProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SafeKeeperSpec) DeepCopyInto(out *SafeKeeperSpec) {
 	*out = *in
@@ -825,6 +1238,12 @@ func (in *SafeKeeperSpec) DeepCopy() *SafeKeeperSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SafeKeeperStatus) DeepCopyInto(out *SafeKeeperStatus) {
 	*out = *in
+	out.WorkloadStatus = in.WorkloadStatus
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]SafeKeeperMember, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -949,6 +1368,7 @@ func (in *StorageBrokerProfile) DeepCopyInto(out *StorageBrokerProfile) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageBrokerProfile.
@@ -1004,6 +1424,16 @@ func (in *StorageBrokerProfileList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageBrokerProfileSpec) DeepCopyInto(out *StorageBrokerProfileSpec) {
 	*out = *in
+	if in.BaseProfileRef != nil {
+		in, out := &in.BaseProfileRef, &out.BaseProfileRef
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(ProfileRolloutSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	in.CommonFields.DeepCopyInto(&out.CommonFields)
 	if in.MinReplicas != nil {
 		in, out := &in.MinReplicas, &out.MinReplicas
@@ -1028,6 +1458,27 @@ func (in *StorageBrokerProfileSpec) DeepCopy() *StorageBrokerProfileSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageBrokerProfileStatus) DeepCopyInto(out *StorageBrokerProfileStatus) {
+	*out = *in
+	in.ProfileStatus.DeepCopyInto(&out.ProfileStatus)
+	if in.Resolved != nil {
+		in, out := &in.Resolved, &out.Resolved
+		*out = new(StorageBrokerProfileSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageBrokerProfileStatus.
+func (in *StorageBrokerProfileStatus) DeepCopy() *StorageBrokerProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageBrokerProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageBrokerSpec) DeepCopyInto(out *StorageBrokerSpec) {
 	*out = *in
@@ -1061,6 +1512,7 @@ func (in *StorageBrokerSpec) DeepCopy() *StorageBrokerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageBrokerStatus) DeepCopyInto(out *StorageBrokerStatus) {
 	*out = *in
+	out.WorkloadStatus = in.WorkloadStatus
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -1105,3 +1557,297 @@ func (in *StorageSpec) DeepCopy() *StorageSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenant) DeepCopyInto(out *Tenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenant.
+func (in *Tenant) DeepCopy() *Tenant {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Tenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantConfigSpec) DeepCopyInto(out *TenantConfigSpec) {
+	*out = *in
+	if in.CheckpointDistance != nil {
+		in, out := &in.CheckpointDistance, &out.CheckpointDistance
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CheckpointTimeout != nil {
+		in, out := &in.CheckpointTimeout, &out.CheckpointTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.CompactionPeriod != nil {
+		in, out := &in.CompactionPeriod, &out.CompactionPeriod
+		*out = new(string)
+		**out = **in
+	}
+	if in.GCHorizon != nil {
+		in, out := &in.GCHorizon, &out.GCHorizon
+		*out = new(int64)
+		**out = **in
+	}
+	if in.GCPeriod != nil {
+		in, out := &in.GCPeriod, &out.GCPeriod
+		*out = new(string)
+		**out = **in
+	}
+	if in.PITRInterval != nil {
+		in, out := &in.PITRInterval, &out.PITRInterval
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImageCreationThreshold != nil {
+		in, out := &in.ImageCreationThreshold, &out.ImageCreationThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WalReceiverConnectTimeout != nil {
+		in, out := &in.WalReceiverConnectTimeout, &out.WalReceiverConnectTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.LaggingWalTimeout != nil {
+		in, out := &in.LaggingWalTimeout, &out.LaggingWalTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxLsnWalLag != nil {
+		in, out := &in.MaxLsnWalLag, &out.MaxLsnWalLag
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantConfigSpec.
+func (in *TenantConfigSpec) DeepCopy() *TenantConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantList) DeepCopyInto(out *TenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Tenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantList.
+func (in *TenantList) DeepCopy() *TenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
+	*out = *in
+	out.NeonClusterRef = in.NeonClusterRef
+	in.Config.DeepCopyInto(&out.Config)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
+func (in *TenantSpec) DeepCopy() *TenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
+func (in *TenantStatus) DeepCopy() *TenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Timeline) DeepCopyInto(out *Timeline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timeline.
+func (in *Timeline) DeepCopy() *Timeline {
+	if in == nil {
+		return nil
+	}
+	out := new(Timeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Timeline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimelineList) DeepCopyInto(out *TimelineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Timeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimelineList.
+func (in *TimelineList) DeepCopy() *TimelineList {
+	if in == nil {
+		return nil
+	}
+	out := new(TimelineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TimelineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimelineSpec) DeepCopyInto(out *TimelineSpec) {
+	*out = *in
+	out.TenantRef = in.TenantRef
+	if in.AncestorTimelineRef != nil {
+		in, out := &in.AncestorTimelineRef, &out.AncestorTimelineRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.AncestorLSN != nil {
+		in, out := &in.AncestorLSN, &out.AncestorLSN
+		*out = new(string)
+		**out = **in
+	}
+	if in.AncestorTimestamp != nil {
+		in, out := &in.AncestorTimestamp, &out.AncestorTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.PGVersion != nil {
+		in, out := &in.PGVersion, &out.PGVersion
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimelineSpec.
+func (in *TimelineSpec) DeepCopy() *TimelineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TimelineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimelineStatus) DeepCopyInto(out *TimelineStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimelineStatus.
+func (in *TimelineStatus) DeepCopy() *TimelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TimelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadStatus.
+func (in *WorkloadStatus) DeepCopy() *WorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+const (
+	// HTTPPort is the port of the compute_ctl HTTP API
+	HTTPPort = 3080
+
+	pgDataPath = "/var/db/postgres/compute"
+)
+
+// makeComputeDeployment creates a Deployment for the ComputeEndpoint
+func makeComputeDeployment(ep *v1alpha1.ComputeEndpoint, spec *appsv1.DeploymentSpec) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ep.Name,
+			Namespace: ep.Namespace,
+		},
+		Spec: *spec,
+	}
+
+	operator.UpdateObject(deployment,
+		operator.WithLabels(ep.Labels),
+		operator.WithOwner(ep),
+	)
+
+	return deployment
+}
+
+func makeComputeDeploymentSpec(ep *v1alpha1.ComputeEndpoint) *appsv1.DeploymentSpec {
+	cpf := ep.Spec.CommonFields
+
+	image := k8sutils.NeonDefaultImage
+	if cpf.Image != nil {
+		image = *cpf.Image
+	}
+
+	// A single primary per timeline, safekeepers reject a second writer
+	replicas := int32(1)
+
+	labels := map[string]string{
+		"app":       ep.Name,
+		"component": "compute-deployment",
+	}
+
+	args := []string{
+		fmt.Sprintf("--pgdata=%s/pgdata", pgDataPath),
+		fmt.Sprintf("--connstr=postgresql://cloud_admin@localhost:%d/postgres", controlplane.ComputePostgresPort),
+		"--pgbin=/usr/local/bin/postgres",
+		fmt.Sprintf("--compute-id=%s", controlplane.ComputeID(ep)),
+		// compute_ctl fetches its spec from the control plane, later changes are pushed to it
+		fmt.Sprintf("--control-plane-uri=%s", controlplane.GetServiceURL()),
+	}
+
+	container := corev1.Container{
+		Name:            "compute",
+		Image:           image,
+		ImagePullPolicy: cpf.ImagePullPolicy,
+		Resources:       cpf.Resources,
+		Command:         []string{"compute_ctl"},
+		Args:            args,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "postgres",
+				ContainerPort: controlplane.ComputePostgresPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+			{
+				Name:          "http",
+				ContainerPort: HTTPPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromInt(controlplane.ComputePostgresPort),
+				},
+			},
+			PeriodSeconds: 5,
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "pgdata",
+				MountPath: pgDataPath,
+			},
+		},
+	}
+
+	if controlplane.GetEnableJWT() {
+		// compute_ctl reads the token on startup, it only needs it to fetch its spec
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: "NEON_CONTROL_PLANE_TOKEN",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: controlplane.TokenSecretName(ep.Name),
+					},
+					Key: controlplane.TokenSecretKey,
+				},
+			},
+		})
+	}
+
+	volumes := []corev1.Volume{
+		{
+			// Compute data is a cache of the pageserver, it does not need to survive restarts
+			Name: "pgdata",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+
+	podTemplateSpec := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: labels,
+		},
+		Spec: corev1.PodSpec{
+			Containers:       []corev1.Container{container},
+			ImagePullSecrets: cpf.ImagePullSecrets,
+			NodeSelector:     cpf.NodeSelector,
+			Affinity:         cpf.Affinity,
+			SecurityContext:  cpf.SecurityContext,
+			Volumes:          volumes,
+		},
+	}
+
+	return &appsv1.DeploymentSpec{
+		Replicas: &replicas,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: labels,
+		},
+		// Never run the old and the new compute side by side
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RecreateDeploymentStrategyType,
+		},
+		Template: podTemplateSpec,
+	}
+}
+
+// makeComputeService creates a Service exposing Postgres of the ComputeEndpoint
+func makeComputeService(ep *v1alpha1.ComputeEndpoint) *corev1.Service {
+	serviceType := ep.Spec.ServiceType
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ep.Name,
+			Namespace: ep.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: serviceType,
+			Selector: map[string]string{
+				"app": ep.Name,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "postgres",
+					Port:       controlplane.ComputePostgresPort,
+					TargetPort: intstr.FromInt(controlplane.ComputePostgresPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+
+	operator.UpdateObject(service,
+		operator.WithLabels(ep.Labels),
+		operator.WithOwner(ep),
+	)
+
+	return service
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+)
+
+// Operator manages lifecycle for ComputeEndpoint resources.
+type Operator struct {
+	nclient  client.Client
+	kclient  kubernetes.Interface
+	scheme   *runtime.Scheme
+	logger   *slog.Logger
+	specs    *controlplane.ComputeSpecBuilder
+	notifier *controlplane.ComputeNotifier
+}
+
+// New creates a new ComputeEndpoint Operator.
+func New(nclient client.Client, scheme *runtime.Scheme, logger *slog.Logger, config *rest.Config) (*Operator, error) {
+	logger = logger.With("component", controllerName)
+
+	// Create kubernetes clientset for direct client-go operations
+	kclient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	return &Operator{
+		logger:   logger,
+		nclient:  nclient,
+		kclient:  kclient,
+		scheme:   scheme,
+		specs:    controlplane.NewComputeSpecBuilder(nclient, kclient),
+		notifier: controlplane.NewComputeNotifier(nclient, kclient, logger),
+	}, nil
+}
+
+// sync reconciles the ComputeEndpoint resource state with the desired state.
+func (o *Operator) sync(ctx context.Context, name, namespace string) error {
+
+	ep := &v1alpha1.ComputeEndpoint{}
+	if err := o.nclient.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, ep); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	ep = ep.DeepCopy()
+
+	key := fmt.Sprintf("%s/%s", namespace, name)
+
+	logger := o.logger.With("key", key)
+	logger.Info("syncing computeendpoint")
+
+	spec, err := o.specs.Build(ctx, ep)
+	if err != nil {
+		if errors.Is(err, controlplane.ErrComputeNotReady) {
+			// The compute is reconciled again when its timeline or tenant changes
+			logger.Info("waiting for compute dependencies", "reason", err)
+			return o.updateStatus(ctx, ep, nil, metav1.Condition{
+				Type:    v1alpha1.ComputeEndpointConditionReady,
+				Status:  metav1.ConditionFalse,
+				Reason:  "Pending",
+				Message: err.Error(),
+			})
+		}
+		return fmt.Errorf("failed to build compute spec: %w", err)
+	}
+
+	// The token authenticates compute_ctl to the control plane when it fetches its spec
+	if _, err := controlplane.UpdateTokenSecret(ctx, o.kclient, ep,
+		controlplane.ComputeTokenSubject(controlplane.ComputeID(ep)), controlplane.ScopeCompute,
+		map[string]string{
+			"app":       ep.GetName(),
+			"component": "compute-token",
+		}); err != nil {
+		return fmt.Errorf("failed to reconcile compute token secret: %w", err)
+	}
+
+	deployment, err := o.updateDeployment(ctx, ep)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile compute deployment: %w", err)
+	}
+
+	if err := o.updateService(ctx, ep); err != nil {
+		return fmt.Errorf("failed to reconcile compute service: %w", err)
+	}
+
+	ep.Status.TenantID = spec.TenantID
+	ep.Status.TimelineID = spec.TimelineID
+
+	cond := metav1.Condition{
+		Type:    v1alpha1.ComputeEndpointConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Starting",
+		Message: "compute pod is not ready",
+	}
+	if deployment.Status.ReadyReplicas > 0 && deployment.Status.ObservedGeneration >= deployment.Generation {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Running"
+		cond.Message = "compute is accepting connections"
+	}
+
+	if err := o.updateStatus(ctx, ep, deployment, cond); err != nil {
+		return err
+	}
+
+	if cond.Status == metav1.ConditionTrue && ep.Status.ConfiguredPageServerConnstring != spec.PageserverConnstring {
+		// The tenant moved since the compute was last configured, or its notification failed
+		if err := o.notifier.Notify(ctx, ep); err != nil {
+			return fmt.Errorf("failed to notify compute: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (o *Operator) updateDeployment(ctx context.Context, ep *v1alpha1.ComputeEndpoint) (*appsv1.Deployment, error) {
+	dep, err := o.kclient.AppsV1().Deployments(ep.GetNamespace()).Get(ctx, ep.GetName(), metav1.GetOptions{})
+	notFound := false
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			notFound = true
+		} else {
+			return nil, fmt.Errorf("failed to get compute deployment: %w", err)
+		}
+	}
+
+	spec := makeComputeDeploymentSpec(ep)
+	deployment := makeComputeDeployment(ep, spec)
+	hash, err := k8sutils.CreateInputHash(ep.ObjectMeta, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create input hash for compute deployment: %w", err)
+	}
+
+	if notFound {
+		if deployment.Annotations == nil {
+			deployment.Annotations = make(map[string]string)
+		}
+		deployment.Annotations[k8sutils.InputHashAnnotationKey] = hash
+
+		created, err := o.kclient.AppsV1().Deployments(ep.GetNamespace()).Create(ctx, deployment, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create compute deployment: %w", err)
+		}
+		return created, nil
+	}
+
+	if dep.Annotations[k8sutils.InputHashAnnotationKey] == hash {
+		// No update needed
+		return dep, nil
+	}
+
+	dep.Spec = deployment.Spec
+	dep.Labels = deployment.Labels
+	if dep.Annotations == nil {
+		dep.Annotations = make(map[string]string)
+	}
+	maps.Copy(dep.Annotations, deployment.Annotations)
+	dep.Annotations[k8sutils.InputHashAnnotationKey] = hash
+
+	updated, err := o.kclient.AppsV1().Deployments(ep.GetNamespace()).Update(ctx, dep, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update compute deployment: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (o *Operator) updateService(ctx context.Context, ep *v1alpha1.ComputeEndpoint) error {
+	svc, err := o.kclient.CoreV1().Services(ep.GetNamespace()).Get(ctx, ep.GetName(), metav1.GetOptions{})
+	notFound := false
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			notFound = true
+		} else {
+			return fmt.Errorf("failed to get compute service: %w", err)
+		}
+	}
+
+	service := makeComputeService(ep)
+
+	hash, err := k8sutils.CreateInputHash(ep.ObjectMeta, service.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to create input hash for compute service: %w", err)
+	}
+
+	if notFound {
+		if service.Annotations == nil {
+			service.Annotations = make(map[string]string)
+		}
+		service.Annotations[k8sutils.InputHashAnnotationKey] = hash
+
+		_, err = o.kclient.CoreV1().Services(ep.GetNamespace()).Create(ctx, service, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create compute service: %w", err)
+		}
+		return nil
+	}
+
+	if svc.Annotations[k8sutils.InputHashAnnotationKey] == hash {
+		// No update needed
+		return nil
+	}
+
+	// Keep the allocated cluster IP and node ports
+	svc.Spec.Type = service.Spec.Type
+	svc.Spec.Selector = service.Spec.Selector
+	svc.Spec.Ports = service.Spec.Ports
+	svc.Labels = service.Labels
+	if svc.Annotations == nil {
+		svc.Annotations = make(map[string]string)
+	}
+	maps.Copy(svc.Annotations, service.Annotations)
+	svc.Annotations[k8sutils.InputHashAnnotationKey] = hash
+
+	_, err = o.kclient.CoreV1().Services(ep.GetNamespace()).Update(ctx, svc, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update compute service: %w", err)
+	}
+
+	return nil
+}
+
+// updateStatus persists the ComputeEndpoint status with the given Ready condition
+func (o *Operator) updateStatus(ctx context.Context, ep *v1alpha1.ComputeEndpoint, deployment *appsv1.Deployment, cond metav1.Condition) error {
+	ep.Status.ObservedGeneration = ep.Generation
+	ep.Status.ReadyReplicas = 0
+	if deployment != nil {
+		ep.Status.ServiceName = ep.Name
+		ep.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+	}
+
+	cond.ObservedGeneration = ep.Generation
+	meta.SetStatusCondition(&ep.Status.Conditions, cond)
+
+	if err := o.nclient.Status().Update(ctx, ep); err != nil {
+		return fmt.Errorf("failed to update computeendpoint status: %w", err)
+	}
+	return nil
+}
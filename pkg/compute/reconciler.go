@@ -0,0 +1,136 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compute
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+const controllerName = "compute-controller"
+
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=computeendpoints,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=computeendpoints/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=computeendpoints/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=timelines,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=tenants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+func (r *Operator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if err := r.sync(ctx, req.Name, req.Namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Operator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Children are owned without a controller reference
+		For(&corev1alpha1.ComputeEndpoint{}).
+		Owns(&appsv1.Deployment{}, builder.MatchEveryOwner).
+		Owns(&corev1.Service{}, builder.MatchEveryOwner).
+		Owns(&corev1.Secret{}, builder.MatchEveryOwner).
+		Watches(
+			&corev1alpha1.Timeline{},
+			handler.EnqueueRequestsFromMapFunc(r.mapTimelineToComputeEndpoints),
+		).
+		Watches(
+			&corev1alpha1.Tenant{},
+			handler.EnqueueRequestsFromMapFunc(r.mapTenantToComputeEndpoints),
+		).
+		Named("computeendpoint").
+		Complete(r)
+}
+
+// mapTimelineToComputeEndpoints maps a Timeline change to all ComputeEndpoints running on it.
+func (r *Operator) mapTimelineToComputeEndpoints(ctx context.Context, obj client.Object) []reconcile.Request {
+	tl, ok := obj.(*corev1alpha1.Timeline)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	return r.listComputeEndpoints(ctx, tl.Namespace, map[string]bool{tl.Name: true})
+}
+
+// mapTenantToComputeEndpoints maps a Tenant change to all ComputeEndpoints running on one of its timelines.
+// A tenant moving to another pageserver changes the pageserver connection string of its computes.
+func (r *Operator) mapTenantToComputeEndpoints(ctx context.Context, obj client.Object) []reconcile.Request {
+	tn, ok := obj.(*corev1alpha1.Tenant)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	timelines := &corev1alpha1.TimelineList{}
+	if err := r.nclient.List(ctx, timelines, client.InNamespace(tn.Namespace)); err != nil {
+		r.logger.Error("failed to list timelines", "error", err)
+		return []reconcile.Request{}
+	}
+
+	names := make(map[string]bool)
+	for _, tl := range timelines.Items {
+		if tl.Spec.TenantRef.Name == tn.Name {
+			names[tl.Name] = true
+		}
+	}
+
+	return r.listComputeEndpoints(ctx, tn.Namespace, names)
+}
+
+// listComputeEndpoints returns a request for every ComputeEndpoint in the namespace running on one of the timelines.
+func (r *Operator) listComputeEndpoints(ctx context.Context, namespace string, timelines map[string]bool) []reconcile.Request {
+	if len(timelines) == 0 {
+		return []reconcile.Request{}
+	}
+
+	endpoints := &corev1alpha1.ComputeEndpointList{}
+	if err := r.nclient.List(ctx, endpoints, client.InNamespace(namespace)); err != nil {
+		r.logger.Error("failed to list computeendpoints", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, ep := range endpoints.Items {
+		if timelines[ep.Spec.TimelineRef.Name] {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      ep.Name,
+					Namespace: ep.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}
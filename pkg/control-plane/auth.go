@@ -0,0 +1,109 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Reasons of the rejected requests, reported in the responses and in the rejections metric
+const (
+	// AuthReasonMissingToken is a request without a bearer token
+	AuthReasonMissingToken = "missing_token"
+	// AuthReasonInvalidToken is a token with a bad signature, an unknown key or malformed claims
+	AuthReasonInvalidToken = "invalid_token"
+	// AuthReasonExpiredToken is a token past its expiry
+	AuthReasonExpiredToken = "expired_token"
+	// AuthReasonInsufficientScope is a valid token whose scope does not grant the route
+	AuthReasonInsufficientScope = "insufficient_scope"
+	// AuthReasonSubjectMismatch is a valid token for another subject than the resource requested
+	AuthReasonSubjectMismatch = "subject_mismatch"
+)
+
+// authRejections counts the requests rejected by the control plane authentication
+var authRejections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "control_plane_auth_rejections_total",
+		Help: "Number of control plane requests rejected by authentication, by route and reason",
+	},
+	[]string{"route", "reason"},
+)
+
+func init() {
+	// Served by the metrics endpoint of the manager
+	metrics.Registry.MustRegister(authRejections)
+}
+
+// AuthError is the body of the 401 and 403 responses
+type AuthError struct {
+	Msg    string `json:"msg"`
+	Reason string `json:"reason"`
+}
+
+// claimsContextKey is the context key of the claims of an authenticated request
+type claimsContextKey struct{}
+
+// requestClaims returns the claims of the token of an authenticated request, nil when JWT is disabled
+func requestClaims(ctx context.Context) *TokenClaims {
+	claims, _ := ctx.Value(claimsContextKey{}).(*TokenClaims)
+	return claims
+}
+
+// authenticate returns a handler requiring a bearer token of the scope when JWT is enabled.
+// Admin tokens are accepted on every route. The claims are passed to next through the request context.
+func (cps *ControlPlaneServer) authenticate(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !GetEnableJWT() {
+			next(w, r)
+			return
+		}
+
+		tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || tokenString == "" {
+			cps.rejectRequest(w, r, http.StatusUnauthorized, AuthReasonMissingToken, "missing bearer token")
+			return
+		}
+
+		claims, err := cps.jwtManager.VerifyToken(tokenString)
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
+			cps.rejectRequest(w, r, http.StatusUnauthorized, AuthReasonExpiredToken, "token is expired")
+			return
+		case err != nil:
+			cps.rejectRequest(w, r, http.StatusUnauthorized, AuthReasonInvalidToken, err.Error())
+			return
+		case claims.ExpiresAt == nil:
+			// Every token issued by the control plane expires
+			cps.rejectRequest(w, r, http.StatusUnauthorized, AuthReasonInvalidToken, "token has no expiry")
+			return
+		}
+
+		if claims.Scope != scope && claims.Scope != ScopeAdmin {
+			cps.rejectRequest(w, r, http.StatusForbidden, AuthReasonInsufficientScope,
+				fmt.Sprintf("scope %q does not grant this route, %q is required", claims.Scope, scope))
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), claimsContextKey{}, claims)))
+	}
+}
+
+// rejectRequest writes an authentication error response and counts it
+func (cps *ControlPlaneServer) rejectRequest(w http.ResponseWriter, r *http.Request, status int, reason, msg string) {
+	authRejections.WithLabelValues(r.Pattern, reason).Inc()
+	cps.logger.Info("rejected control plane request", "route", r.Pattern, "remote", r.RemoteAddr, "reason", reason, "error", msg)
+
+	switch {
+	case reason == AuthReasonMissingToken:
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	case status == http.StatusUnauthorized:
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	}
+	cps.writeJSON(w, status, AuthError{Msg: msg, Reason: reason})
+}
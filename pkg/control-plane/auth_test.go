@@ -0,0 +1,317 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// newTestJWTManager returns a JWT manager signing with the first of the keys and trusting all of them
+func newTestJWTManager(t *testing.T, keyIDs ...string) (*JWTManager, map[string]*rsa.PrivateKey) {
+	t.Helper()
+
+	privateKeys := make(map[string]*rsa.PrivateKey, len(keyIDs))
+	publicKeys := make(map[string]*rsa.PublicKey, len(keyIDs))
+	for _, keyID := range keyIDs {
+		key, err := rsa.GenerateKey(rand.Reader, rotationKeyBits)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		privateKeys[keyID] = key
+		publicKeys[keyID] = &key.PublicKey
+	}
+
+	// loadedAt is in the future so that the keys are never read from the keys directory
+	return &JWTManager{
+		publicKeys:  publicKeys,
+		privateKey:  privateKeys[keyIDs[0]],
+		activeKeyID: keyIDs[0],
+		loadedAt:    time.Now().Add(time.Hour),
+		logger:      slog.New(slog.DiscardHandler),
+	}, privateKeys
+}
+
+// enableTestJWT enables JWT with the manager as the token issuer for the duration of the test
+func enableTestJWT(t *testing.T, jm *JWTManager) {
+	t.Helper()
+
+	previousEnabled, previousIssuer := enableJWT, issuer
+	enableJWT, issuer = true, jm
+	t.Cleanup(func() {
+		enableJWT, issuer = previousEnabled, previousIssuer
+	})
+}
+
+// rejections returns the count of the rejections metric of a route and a reason
+func rejections(t *testing.T, route, reason string) float64 {
+	t.Helper()
+
+	metric := &dto.Metric{}
+	if err := authRejections.WithLabelValues(route, reason).Write(metric); err != nil {
+		t.Fatalf("failed to read rejections metric: %v", err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestAuthenticate(t *testing.T) {
+	jm, privateKeys := newTestJWTManager(t, "k1")
+	enableTestJWT(t, jm)
+
+	// unknownKey signs with a key the control plane never published
+	unknownKey, err := rsa.GenerateKey(rand.Reader, rotationKeyBits)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signWith := func(key *rsa.PrivateKey, keyID string, claims TokenClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = keyID
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+	generate := func(scope Scope, ttl time.Duration) string {
+		token, _, err := jm.GenerateToken("pageserver/neon/ps", scope, "", ttl)
+		if err != nil {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		return token
+	}
+	validClaims := TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "pageserver/neon/ps",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Scope: ScopeGenerationsAPI,
+	}
+	noExpiry := validClaims
+	noExpiry.ExpiresAt = nil
+	computeToken, err := jm.GenerateComputeToken(controllerName, "neon.compute", time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateComputeToken() error = %v", err)
+	}
+
+	const route = "POST /upcall/v1/validate"
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantReason    string
+		wantChallenge string
+	}{
+		{
+			name:          "missing token",
+			wantStatus:    http.StatusUnauthorized,
+			wantReason:    AuthReasonMissingToken,
+			wantChallenge: "Bearer",
+		},
+		{
+			name:          "not a bearer token",
+			authorization: "Basic dXNlcjpwYXNz",
+			wantStatus:    http.StatusUnauthorized,
+			wantReason:    AuthReasonMissingToken,
+			wantChallenge: "Bearer",
+		},
+		{
+			name:          "expired token",
+			authorization: "Bearer " + generate(ScopeGenerationsAPI, -time.Minute),
+			wantStatus:    http.StatusUnauthorized,
+			wantReason:    AuthReasonExpiredToken,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "unknown key ID",
+			authorization: "Bearer " + signWith(unknownKey, "k0", validClaims),
+			wantStatus:    http.StatusUnauthorized,
+			wantReason:    AuthReasonInvalidToken,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "key ID of another key",
+			authorization: "Bearer " + signWith(unknownKey, "k1", validClaims),
+			wantStatus:    http.StatusUnauthorized,
+			wantReason:    AuthReasonInvalidToken,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "token without expiry",
+			authorization: "Bearer " + signWith(privateKeys["k1"], "k1", noExpiry),
+			wantStatus:    http.StatusUnauthorized,
+			wantReason:    AuthReasonInvalidToken,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "insufficient scope",
+			authorization: "Bearer " + generate(ScopeTenant, time.Hour),
+			wantStatus:    http.StatusForbidden,
+			wantReason:    AuthReasonInsufficientScope,
+		},
+		{
+			name:          "compute_ctl token",
+			authorization: "Bearer " + computeToken,
+			wantStatus:    http.StatusForbidden,
+			wantReason:    AuthReasonInsufficientScope,
+		},
+		{
+			name:          "valid token",
+			authorization: "Bearer " + generate(ScopeGenerationsAPI, time.Hour),
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "admin token",
+			authorization: "Bearer " + generate(ScopeAdmin, time.Hour),
+			wantStatus:    http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cps := &ControlPlaneServer{
+				logger:     slog.New(slog.DiscardHandler),
+				jwtManager: jm,
+			}
+			var claims *TokenClaims
+			mux := http.NewServeMux()
+			mux.HandleFunc(route, cps.authenticate(ScopeGenerationsAPI, func(w http.ResponseWriter, r *http.Request) {
+				claims = requestClaims(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var before float64
+			if tt.wantReason != "" {
+				before = rejections(t, route, tt.wantReason)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/upcall/v1/validate", strings.NewReader("{}"))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantChallenge)
+			}
+
+			if tt.wantReason == "" {
+				if claims == nil || claims.Subject != "pageserver/neon/ps" {
+					t.Errorf("claims passed to the handler = %+v", claims)
+				}
+				return
+			}
+
+			authErr := AuthError{}
+			if err := json.NewDecoder(rec.Body).Decode(&authErr); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if authErr.Reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", authErr.Reason, tt.wantReason)
+			}
+			if got := rejections(t, route, tt.wantReason); got != before+1 {
+				t.Errorf("rejections{route=%q, reason=%q} = %v, want %v", route, tt.wantReason, got, before+1)
+			}
+		})
+	}
+}
+
+func TestComputeSpecSubjectMismatch(t *testing.T) {
+	jm, _ := newTestJWTManager(t, "k1")
+	enableTestJWT(t, jm)
+
+	cps := &ControlPlaneServer{
+		mux:        http.NewServeMux(),
+		logger:     slog.New(slog.DiscardHandler),
+		jwtManager: jm,
+	}
+	cps.registerComputeRoutes()
+
+	token, _, err := IssueToken(ComputeTokenSubject("neon.other"), ScopeCompute, "")
+	if err != nil {
+		t.Fatalf("IssueToken() error = %v", err)
+	}
+	route := "GET " + computeSpecPath
+	before := rejections(t, route, AuthReasonSubjectMismatch)
+
+	req := httptest.NewRequest(http.MethodGet, strings.Replace(computeSpecPath, "{id}", "neon.compute", 1), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	cps.mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusForbidden, rec.Body)
+	}
+	if got := rejections(t, route, AuthReasonSubjectMismatch); got != before+1 {
+		t.Errorf("rejections{route=%q, reason=%q} = %v, want %v", route, AuthReasonSubjectMismatch, got, before+1)
+	}
+}
+
+func TestGenerateComputeToken(t *testing.T) {
+	jm, _ := newTestJWTManager(t, "k1")
+
+	token, err := jm.GenerateComputeToken(controllerName, "neon.compute", computeTokenTTL)
+	if err != nil {
+		t.Fatalf("GenerateComputeToken() error = %v", err)
+	}
+
+	claims, err := jm.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+	if claims.ComputeID != "neon.compute" {
+		t.Errorf("compute_id = %q, want %q", claims.ComputeID, "neon.compute")
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != ComputeAudience {
+		t.Errorf("aud = %v, want [%s]", claims.Audience, ComputeAudience)
+	}
+	if ttl := time.Until(claims.ExpiresAt.Time); ttl <= 0 || ttl > computeTokenTTL {
+		t.Errorf("token expires in %v, want at most %v", ttl, computeTokenTTL)
+	}
+	if got := tokenKeyID(token); got != "k1" {
+		t.Errorf("kid = %q, want %q", got, "k1")
+	}
+
+	// compute_ctl tokens carry no scope nor tenant, the claims are absent rather than empty
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	if err != nil {
+		t.Fatalf("failed to decode token payload: %v", err)
+	}
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		t.Fatalf("failed to decode token claims: %v", err)
+	}
+	for _, claim := range []string{"scope", "tenant_id"} {
+		if _, ok := raw[claim]; ok {
+			t.Errorf("compute_ctl token has a %s claim: %s", claim, payload)
+		}
+	}
+}
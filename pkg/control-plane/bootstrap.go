@@ -0,0 +1,316 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+const (
+	// CertsSecretName is the Secret of the operator namespace holding the serving certificate of the control plane
+	CertsSecretName = "control-plane-certs"
+	// CASecretName is the Secret of the operator namespace holding the CA generated by the bootstrap and its key.
+	// It is never mounted, the operator reads it to issue the certificates of the components.
+	CASecretName = "control-plane-ca"
+	// CACertSecretName is the Secret of a cluster namespace holding the CA certificate trusted by the components
+	CACertSecretName = "control-plane-ca-cert"
+	// CACertKey is the key of the CA certificate in the certificate Secrets, set when the operator generated it
+	CACertKey = "ca.crt"
+	// CAKeyKey is the key of the CA private key in the CA Secret
+	CAKeyKey = "ca.key"
+
+	// caValidity and servingCertValidity are the lifetimes of the generated certificates
+	caValidity          = 10 * 365 * 24 * time.Hour
+	servingCertValidity = 5 * 365 * 24 * time.Hour
+
+	// bootstrapMountTimeout bounds the wait for the kubelet to project a generated Secret into its volume
+	bootstrapMountTimeout = 3 * time.Minute
+)
+
+// bootstrapSecrets generates the Secrets of the enabled features that do not exist yet in the operator namespace:
+// the JWT keys and a serving certificate signed by a self-signed CA, generated with it unless the CA Secret
+// exists. Existing Secrets are never changed.
+// The Secrets are mounted by the operator pod, it waits until the kubelet projects the generated ones.
+func bootstrapSecrets(ctx context.Context, kclient kubernetes.Interface, withTLS, withJWT bool, logger *slog.Logger) error {
+	namespace := k8sutils.GetOperatorNamespace()
+
+	if withTLS {
+		generated, err := createSecretIfMissing(ctx, kclient, namespace, CertsSecretName, corev1.SecretTypeTLS, func() (map[string][]byte, error) {
+			ca, err := bootstrapCA(ctx, kclient, namespace)
+			if err != nil {
+				return nil, err
+			}
+			return IssueCertificate(ca, ServiceName, ServiceDNSNames(ServiceName, namespace), servingCertValidity)
+		})
+		if err != nil {
+			return err
+		}
+		if generated {
+			logger.Info("Generated the control plane serving certificate", "namespace", namespace, "secret", CertsSecretName)
+			if err := waitForFiles(ctx, certPath, certKeyPath); err != nil {
+				return fmt.Errorf("generated control plane certificate is not mounted: %w", err)
+			}
+		}
+	}
+
+	if withJWT {
+		generated, err := createSecretIfMissing(ctx, kclient, namespace, JWTKeysSecretName, corev1.SecretTypeOpaque, generateJWTKeys)
+		if err != nil {
+			return err
+		}
+		if generated {
+			logger.Info("Generated the JWT keys", "namespace", namespace, "secret", JWTKeysSecretName)
+			if err := waitForFiles(ctx, filepath.Join(jwtKeysDir, activeKeyIDFile)); err != nil {
+				return fmt.Errorf("generated JWT keys are not mounted: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// createSecretIfMissing creates a Secret with the data returned by generate when it does not exist.
+// It reports whether the Secret was generated, by this operator or by another replica starting concurrently.
+func createSecretIfMissing(ctx context.Context, kclient kubernetes.Interface, namespace, name string, secretType corev1.SecretType, generate func() (map[string][]byte, error)) (bool, error) {
+	_, err := kclient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return false, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get %s secret: %w", name, err)
+	}
+
+	data, err := generate()
+	if err != nil {
+		return false, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: secretType,
+		Data: data,
+	}
+	operator.UpdateObject(secret)
+
+	_, err = kclient.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return false, fmt.Errorf("failed to create %s secret: %w", name, err)
+	}
+	return true, nil
+}
+
+// waitForFiles waits until every file exists, the kubelet projects Secret changes into volumes with a delay
+func waitForFiles(ctx context.Context, paths ...string) error {
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, bootstrapMountTimeout, true, func(context.Context) (bool, error) {
+		for _, path := range paths {
+			if _, err := os.Stat(path); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return false, nil
+				}
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// generateJWTKeys returns the data of a JWT keys Secret holding a single RSA key pair, the signing key.
+// The keys are RSA as the tokens are signed with RS256.
+func generateJWTKeys() (map[string][]byte, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, rotationKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT key: %w", err)
+	}
+	privatePEM, publicPEM, err := EncodeKeyPair(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := newKeyID()
+	return map[string][]byte{
+		activeKeyIDFile:          []byte(keyID),
+		keyID + privateKeySuffix: privatePEM,
+		keyID + publicKeySuffix:  publicPEM,
+	}, nil
+}
+
+// bootstrapCA returns the data of the CA Secret, generating a self-signed CA when it does not exist.
+// The Secret is read back, another replica starting concurrently may have created it.
+func bootstrapCA(ctx context.Context, kclient kubernetes.Interface, namespace string) (map[string][]byte, error) {
+	if _, err := createSecretIfMissing(ctx, kclient, namespace, CASecretName, corev1.SecretTypeOpaque, generateCA); err != nil {
+		return nil, err
+	}
+	secret, err := kclient.CoreV1().Secrets(namespace).Get(ctx, CASecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s secret: %w", CASecretName, err)
+	}
+	if len(secret.Data[CACertKey]) == 0 || len(secret.Data[CAKeyKey]) == 0 {
+		return nil, fmt.Errorf("%s secret does not hold %s and %s", CASecretName, CACertKey, CAKeyKey)
+	}
+	return secret.Data, nil
+}
+
+// generateCA returns the data of a CA Secret holding a new self-signed CA and its key
+func generateCA() (map[string][]byte, error) {
+	now := time.Now()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+	caSerial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          caSerial,
+		Subject:               pkix.Name{CommonName: ServiceName + "-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	caKeyDER, err := x509.MarshalPKCS8PrivateKey(caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CA key: %w", err)
+	}
+
+	return map[string][]byte{
+		CACertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		CAKeyKey:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: caKeyDER}),
+	}, nil
+}
+
+// ServiceDNSNames returns the DNS names of a Service in a namespace
+func ServiceDNSNames(name, namespace string) []string {
+	return []string{
+		name,
+		name + "." + namespace,
+		name + "." + namespace + ".svc",
+		name + "." + namespace + ".svc.cluster.local",
+	}
+}
+
+// IssueCertificate returns the data of a TLS Secret holding a serving certificate for the DNS names,
+// signed by the CA of a CA Secret, and the CA certificate.
+func IssueCertificate(ca map[string][]byte, commonName string, dnsNames []string, validity time.Duration) (map[string][]byte, error) {
+	caCert, caKey, err := parseCA(ca)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serving key: %w", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create serving certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode serving key: %w", err)
+	}
+
+	return map[string][]byte{
+		corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		CACertKey:               ca[CACertKey],
+	}, nil
+}
+
+// parseCA returns the certificate and the private key of a CA Secret
+func parseCA(ca map[string][]byte) (*x509.Certificate, crypto.Signer, error) {
+	certBlock, _ := pem.Decode(ca[CACertKey])
+	if certBlock == nil {
+		return nil, nil, fmt.Errorf("failed to decode %s", CACertKey)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	keyBlock, _ := pem.Decode(ca[CAKeyKey])
+	if keyBlock == nil {
+		return nil, nil, fmt.Errorf("failed to decode %s", CAKeyKey)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("CA key of type %T cannot sign", key)
+	}
+	return cert, signer, nil
+}
+
+// newSerialNumber returns a random certificate serial number
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial number: %w", err)
+	}
+	return serial, nil
+}
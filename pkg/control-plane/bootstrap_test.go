@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIssueCertificate(t *testing.T) {
+	ca, err := generateCA()
+	if err != nil {
+		t.Fatalf("generateCA() error = %v", err)
+	}
+
+	data, err := IssueCertificate(ca, "cluster-pageserver", []string{
+		"cluster-pageserver.neon.svc",
+		"*.cluster-pageserver.neon.svc",
+	}, time.Hour)
+	if err != nil {
+		t.Fatalf("IssueCertificate() error = %v", err)
+	}
+	if _, ok := data[CAKeyKey]; ok {
+		t.Fatalf("issued certificate Secret holds the CA key")
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(data[CACertKey]) {
+		t.Fatalf("failed to parse the CA certificate of the issued certificate Secret")
+	}
+	block, _ := pem.Decode(data[corev1.TLSCertKey])
+	if block == nil {
+		t.Fatalf("failed to decode the issued certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse the issued certificate: %v", err)
+	}
+
+	for _, name := range []string{"cluster-pageserver.neon.svc", "cluster-pageserver-0.cluster-pageserver.neon.svc"} {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: name, Roots: roots}); err != nil {
+			t.Errorf("certificate does not verify for %s: %v", name, err)
+		}
+	}
+	// The certificates of the components cannot impersonate the control plane
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: ServiceName + ".neon.svc", Roots: roots}); err == nil {
+		t.Errorf("certificate verifies for the control plane")
+	}
+}
@@ -0,0 +1,117 @@
+package controlplane
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=computeendpoints;timelines;tenants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+const (
+	// computeSpecPath is the path compute_ctl fetches its spec from, relative to --control-plane-uri
+	computeSpecPath = "/compute/api/v2/computes/{id}/spec"
+
+	// ComputeStatusAttached tells compute_ctl that the spec is complete and Postgres can be started
+	ComputeStatusAttached = "attached"
+	// ComputeStatusEmpty tells compute_ctl to wait for a spec pushed through its /configure endpoint
+	ComputeStatusEmpty = "empty"
+)
+
+// ComputeSpecResponse is the response to a compute spec request.
+type ComputeSpecResponse struct {
+	Spec             *ComputeSpec     `json:"spec"`
+	Status           string           `json:"status"`
+	ComputeCtlConfig ComputeCtlConfig `json:"compute_ctl_config"`
+}
+
+// ComputeCtlConfig is the compute_ctl configuration sent along with the spec.
+type ComputeCtlConfig struct {
+	JWKS JWKSet `json:"jwks"`
+}
+
+// JWKSet is the set of keys compute_ctl accepts for requests to its own API,
+// the public keys of the operator so that it can push specs to /configure.
+type JWKSet struct {
+	Keys []map[string]interface{} `json:"keys"`
+}
+
+// registerComputeRoutes registers the compute_ctl API handlers, authenticated with compute tokens
+func (cps *ControlPlaneServer) registerComputeRoutes() {
+	cps.mux.HandleFunc("GET "+computeSpecPath, cps.authenticate(ScopeCompute, cps.handleComputeSpec))
+}
+
+// ComputeTokenSubject returns the subject of the token of a compute, it only grants the spec of that compute
+func ComputeTokenSubject(computeID string) string {
+	return "compute/" + computeID
+}
+
+// handleComputeSpec serves GET /compute/api/v2/computes/{id}/spec.
+// The compute ID is <namespace>.<name> of the ComputeEndpoint. Computes whose timeline
+// is not ready yet get an empty spec and wait until one is pushed to them.
+func (cps *ControlPlaneServer) handleComputeSpec(w http.ResponseWriter, r *http.Request) {
+	computeID := r.PathValue("id")
+
+	// Namespaces cannot contain dots, names can
+	namespace, name, ok := strings.Cut(computeID, ".")
+	if !ok || namespace == "" || name == "" {
+		cps.writeError(w, http.StatusBadRequest, "invalid compute id: "+computeID)
+		return
+	}
+
+	if claims := requestClaims(r.Context()); claims != nil && claims.Scope == ScopeCompute && claims.Subject != ComputeTokenSubject(computeID) {
+		cps.rejectRequest(w, r, http.StatusForbidden, AuthReasonSubjectMismatch, "token does not grant compute "+computeID)
+		return
+	}
+
+	ep := &v1alpha1.ComputeEndpoint{}
+	if err := cps.nclient.Get(r.Context(), client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, ep); err != nil {
+		if apierrors.IsNotFound(err) {
+			cps.writeError(w, http.StatusNotFound, "compute not found: "+computeID)
+			return
+		}
+		cps.logger.Error("failed to get computeendpoint", "computeID", computeID, "error", err)
+		cps.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := ComputeSpecResponse{
+		Status: ComputeStatusEmpty,
+		ComputeCtlConfig: ComputeCtlConfig{
+			JWKS: publishedJWKS(),
+		},
+	}
+
+	spec, err := cps.specs.Build(r.Context(), ep)
+	switch {
+	case errors.Is(err, ErrComputeNotReady):
+		cps.logger.Info("compute spec is not ready", "computeID", computeID, "reason", err)
+	case err != nil:
+		cps.logger.Error("failed to build compute spec", "computeID", computeID, "error", err)
+		cps.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	default:
+		resp.Spec = spec
+		resp.Status = ComputeStatusAttached
+	}
+
+	cps.writeJSON(w, http.StatusOK, resp)
+}
+
+// publishedJWKS returns the keys compute_ctl verifies the requests of the operator with, empty when JWT is disabled
+func publishedJWKS() JWKSet {
+	if !GetEnableJWT() || issuer == nil {
+		return JWKSet{Keys: []map[string]interface{}{}}
+	}
+	return JWKSet{Keys: issuer.JWKS()}
+}
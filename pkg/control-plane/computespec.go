@@ -0,0 +1,321 @@
+package controlplane
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	pageserverapi "github.com/stateless-pg/stateless-pg/pkg/pageserver-api"
+)
+
+const (
+	// ComputePostgresPort is the port Postgres listens on in compute pods
+	ComputePostgresPort = 5432
+	// pageServerPgPort is the port of the pageserver page service
+	pageServerPgPort = 6400
+	// safeKeeperPgPort is the port safekeepers accept WAL on
+	safeKeeperPgPort = 5454
+	// scramIterations is the PBKDF2 iteration count of SCRAM-SHA-256 verifiers, the Postgres default
+	scramIterations = 4096
+)
+
+// ErrComputeNotReady is returned when the timeline, tenant or cluster of a compute is not ready yet.
+var ErrComputeNotReady = errors.New("compute dependencies are not ready")
+
+// ComputeSpec is the compute_ctl spec of a compute, see compute_api::spec::ComputeSpec in neon.
+type ComputeSpec struct {
+	FormatVersion         float32          `json:"format_version"`
+	Cluster               ComputeCluster   `json:"cluster"`
+	DeltaOperations       []ComputeDeltaOp `json:"delta_operations"`
+	SkipPgCatalogUpdates  bool             `json:"skip_pg_catalog_updates"`
+	TenantID              string           `json:"tenant_id"`
+	TimelineID            string           `json:"timeline_id"`
+	PageserverConnstring  string           `json:"pageserver_connstring"`
+	ShardStripeSize       uint32           `json:"shard_stripe_size,omitempty"`
+	SafekeeperConnstrings []string         `json:"safekeeper_connstrings"`
+	Mode                  string           `json:"mode"`
+	StorageAuthToken      string           `json:"storage_auth_token,omitempty"`
+	Features              []string         `json:"features"`
+}
+
+// ComputeCluster describes the roles, databases and settings of a compute.
+type ComputeCluster struct {
+	ClusterID string                 `json:"cluster_id"`
+	Name      string                 `json:"name"`
+	Roles     []ComputeSpecRole      `json:"roles"`
+	Databases []ComputeSpecDatabase  `json:"databases"`
+	Settings  []ComputeGenericOption `json:"settings"`
+}
+
+// ComputeSpecRole is a Postgres role created by compute_ctl.
+type ComputeSpecRole struct {
+	Name              string                 `json:"name"`
+	EncryptedPassword *string                `json:"encrypted_password"`
+	Options           []ComputeGenericOption `json:"options"`
+}
+
+// ComputeSpecDatabase is a Postgres database created by compute_ctl.
+type ComputeSpecDatabase struct {
+	Name    string                 `json:"name"`
+	Owner   string                 `json:"owner"`
+	Options []ComputeGenericOption `json:"options"`
+}
+
+// ComputeGenericOption is a postgresql.conf setting or a role or database option.
+type ComputeGenericOption struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	VarType string `json:"vartype"`
+}
+
+// ComputeDeltaOp is a rename or delete operation applied by compute_ctl.
+type ComputeDeltaOp struct {
+	Action string `json:"action"`
+	Name   string `json:"name"`
+}
+
+// ComputeSpecBuilder builds compute specs from the ComputeEndpoint, Timeline and Tenant resources.
+type ComputeSpecBuilder struct {
+	nclient client.Client
+	kclient kubernetes.Interface
+}
+
+// NewComputeSpecBuilder creates a new compute spec builder.
+func NewComputeSpecBuilder(nclient client.Client, kclient kubernetes.Interface) *ComputeSpecBuilder {
+	return &ComputeSpecBuilder{
+		nclient: nclient,
+		kclient: kclient,
+	}
+}
+
+// ComputeID returns the compute ID of a ComputeEndpoint, unique across namespaces.
+func ComputeID(ep *v1alpha1.ComputeEndpoint) string {
+	return ep.Namespace + "." + ep.Name
+}
+
+// Build returns the compute spec of a ComputeEndpoint.
+// It returns an error wrapping ErrComputeNotReady until the timeline exists and its tenant is attached.
+func (b *ComputeSpecBuilder) Build(ctx context.Context, ep *v1alpha1.ComputeEndpoint) (*ComputeSpec, error) {
+	tl := &v1alpha1.Timeline{}
+	if err := b.nclient.Get(ctx, client.ObjectKey{
+		Name:      ep.Spec.TimelineRef.Name,
+		Namespace: ep.Namespace,
+	}, tl); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("timeline %s not found: %w", ep.Spec.TimelineRef.Name, ErrComputeNotReady)
+		}
+		return nil, fmt.Errorf("failed to get timeline: %w", err)
+	}
+	if !meta.IsStatusConditionTrue(tl.Status.Conditions, v1alpha1.TimelineConditionReady) || tl.Status.TimelineID == "" {
+		return nil, fmt.Errorf("timeline %s is not ready: %w", tl.Name, ErrComputeNotReady)
+	}
+
+	tn := &v1alpha1.Tenant{}
+	if err := b.nclient.Get(ctx, client.ObjectKey{
+		Name:      tl.Spec.TenantRef.Name,
+		Namespace: ep.Namespace,
+	}, tn); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("tenant %s not found: %w", tl.Spec.TenantRef.Name, ErrComputeNotReady)
+		}
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	return b.build(ctx, ep, tl, tn)
+}
+
+// build returns the compute spec of a ComputeEndpoint running on a timeline of the tenant
+func (b *ComputeSpecBuilder) build(ctx context.Context, ep *v1alpha1.ComputeEndpoint, tl *v1alpha1.Timeline, tn *v1alpha1.Tenant) (*ComputeSpec, error) {
+	if !meta.IsStatusConditionTrue(tn.Status.Conditions, v1alpha1.TenantConditionReady) || tn.Status.PageServerPod == "" {
+		return nil, fmt.Errorf("tenant %s is not attached: %w", tn.Name, ErrComputeNotReady)
+	}
+
+	neonClusterName := tn.Spec.NeonClusterRef.Name
+
+	safekeepers, err := b.safeKeeperConnstrings(ctx, neonClusterName, ep.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := b.makeRoles(ctx, ep)
+	if err != nil {
+		return nil, err
+	}
+
+	databases := make([]ComputeSpecDatabase, 0, len(ep.Spec.Databases))
+	for _, db := range ep.Spec.Databases {
+		databases = append(databases, ComputeSpecDatabase{
+			Name:    db.Name,
+			Owner:   db.Owner,
+			Options: []ComputeGenericOption{},
+		})
+	}
+
+	spec := &ComputeSpec{
+		FormatVersion: 1.0,
+		Cluster: ComputeCluster{
+			ClusterID: ComputeID(ep),
+			Name:      ep.Name,
+			Roles:     roles,
+			Databases: databases,
+			Settings:  makeComputeSettings(ep),
+		},
+		DeltaOperations: []ComputeDeltaOp{},
+		TenantID:        tn.Status.TenantID,
+		TimelineID:      tl.Status.TimelineID,
+		PageserverConnstring: fmt.Sprintf("postgresql://no_user@%s.%s-pageserver.%s.svc.cluster.local:%d",
+			tn.Status.PageServerPod, neonClusterName, ep.Namespace, pageServerPgPort),
+		// Tenants are attached unsharded, the stripe size matches their location config
+		ShardStripeSize:       pageserverapi.DefaultStripeSize,
+		SafekeeperConnstrings: safekeepers,
+		Mode:                  "Primary",
+		Features:              []string{},
+	}
+
+	if GetEnableJWT() {
+		// The token is refreshed by pushing a new spec before it expires
+		token, _, err := IssueToken(fmt.Sprintf("compute/%s/%s", ep.Namespace, ep.Name), ScopeTenant, tn.Status.TenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue compute token: %w", err)
+		}
+		spec.StorageAuthToken = token
+	}
+
+	return spec, nil
+}
+
+// safeKeeperConnstrings returns the address of every safekeeper pod of a NeonCluster
+func (b *ComputeSpecBuilder) safeKeeperConnstrings(ctx context.Context, neonClusterName, namespace string) ([]string, error) {
+	ss, err := b.kclient.AppsV1().StatefulSets(namespace).Get(ctx, neonClusterName+"-safekeeper", metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("safekeepers of neoncluster %s not found: %w", neonClusterName, ErrComputeNotReady)
+		}
+		return nil, fmt.Errorf("failed to get safekeeper statefulset: %w", err)
+	}
+
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+
+	connstrings := make([]string, 0, replicas)
+	for i := int32(0); i < replicas; i++ {
+		connstrings = append(connstrings, fmt.Sprintf("%s-%d.%s.%s.svc.cluster.local:%d",
+			ss.Name, i, ss.Spec.ServiceName, namespace, safeKeeperPgPort))
+	}
+
+	return connstrings, nil
+}
+
+// makeRoles returns the roles of a compute with their passwords as SCRAM-SHA-256 verifiers
+func (b *ComputeSpecBuilder) makeRoles(ctx context.Context, ep *v1alpha1.ComputeEndpoint) ([]ComputeSpecRole, error) {
+	roles := make([]ComputeSpecRole, 0, len(ep.Spec.Roles))
+	for _, role := range ep.Spec.Roles {
+		specRole := ComputeSpecRole{
+			Name:    role.Name,
+			Options: []ComputeGenericOption{},
+		}
+
+		if role.PasswordSecretRef != nil {
+			secret, err := b.kclient.CoreV1().Secrets(ep.Namespace).Get(ctx, role.PasswordSecretRef.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get password secret of role %s: %w", role.Name, err)
+			}
+			password, ok := secret.Data[role.PasswordSecretRef.Key]
+			if !ok {
+				return nil, fmt.Errorf("password secret %s has no key %s", secret.Name, role.PasswordSecretRef.Key)
+			}
+
+			// The salt is derived from the endpoint and role so that the spec only changes with the password
+			verifier, err := scramSHA256Verifier(string(password), []byte(string(ep.UID)+"/"+role.Name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash password of role %s: %w", role.Name, err)
+			}
+			specRole.EncryptedPassword = &verifier
+		}
+
+		roles = append(roles, specRole)
+	}
+
+	return roles, nil
+}
+
+// makeComputeSettings returns the postgresql.conf settings of a compute.
+// compute_ctl adds the neon specific settings from the spec itself.
+func makeComputeSettings(ep *v1alpha1.ComputeEndpoint) []ComputeGenericOption {
+	settings := map[string]ComputeGenericOption{
+		"listen_addresses":          {Value: "*", VarType: "string"},
+		"port":                      {Value: fmt.Sprintf("%d", ComputePostgresPort), VarType: "integer"},
+		"max_connections":           {Value: "100", VarType: "integer"},
+		"shared_buffers":            {Value: "128MB", VarType: "string"},
+		"wal_level":                 {Value: "replica", VarType: "enum"},
+		"max_wal_senders":           {Value: "10", VarType: "integer"},
+		"max_replication_slots":     {Value: "10", VarType: "integer"},
+		"wal_log_hints":             {Value: "off", VarType: "bool"},
+		"password_encryption":       {Value: "scram-sha-256", VarType: "enum"},
+		"shared_preload_libraries":  {Value: "neon", VarType: "string"},
+		"synchronous_standby_names": {Value: "walproposer", VarType: "string"},
+		"fsync":                     {Value: "off", VarType: "bool"},
+		"restart_after_crash":       {Value: "off", VarType: "bool"},
+	}
+
+	for name, value := range ep.Spec.PostgresSettings {
+		settings[name] = ComputeGenericOption{Value: value, VarType: "string"}
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	options := make([]ComputeGenericOption, 0, len(names))
+	for _, name := range names {
+		option := settings[name]
+		option.Name = name
+		options = append(options, option)
+	}
+
+	return options
+}
+
+// scramSHA256Verifier returns the SCRAM-SHA-256 verifier of a password in the format stored by Postgres
+func scramSHA256Verifier(password string, seed []byte) (string, error) {
+	sum := sha256.Sum256(seed)
+	salt := sum[:16]
+
+	salted, err := pbkdf2.Key(sha256.New, password, salt, scramIterations, sha256.Size)
+	if err != nil {
+		return "", err
+	}
+
+	clientKey := hmacSHA256(salted, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	serverKey := hmacSHA256(salted, "Server Key")
+
+	return fmt.Sprintf("SCRAM-SHA-256$%d:%s$%s:%s",
+		scramIterations,
+		base64.StdEncoding.EncodeToString(salt),
+		base64.StdEncoding.EncodeToString(storedKey[:]),
+		base64.StdEncoding.EncodeToString(serverKey),
+	), nil
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
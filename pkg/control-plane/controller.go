@@ -1,37 +1,43 @@
 package controlplane
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
 )
 
 // ControlPlaneServer represents the control plane HTTP server
 type ControlPlaneServer struct {
-	mux        *http.ServeMux
-	logger     *slog.Logger
-	server     *http.Server
-	nclient    client.Client
-	kclient    kubernetes.Interface
-	scheme     *runtime.Scheme
-	jwtManager *JWTManager
+	mux         *http.ServeMux
+	logger      *slog.Logger
+	server      *http.Server
+	nclient     client.Client
+	kclient     kubernetes.Interface
+	scheme      *runtime.Scheme
+	jwtManager  *JWTManager
+	generations *GenerationStore
+	nodes       *NodeRegistry
+	specs       *ComputeSpecBuilder
 }
 
 const (
-	ServiceName       = "control-plane"
-	controllerName    = "control-plane"
-	httpPort          = ":9090"
-	httpsPort         = ":9443"
-	certPath          = "/etc/control-plane/certs/tls.crt"
-	certKeyPath       = "/etc/control-plane/certs/tls.key"
-	jwtPublicKeyPath  = "/etc/control-plane-jwt/jwt.pub"
-	jwtPrivateKeyPath = "/etc/control-plane-jwt/jwt.key"
+	ServiceName    = "control-plane"
+	controllerName = "control-plane"
+	httpPort       = ":9090"
+	httpsPort      = ":9443"
+	certPath       = "/etc/control-plane/certs/tls.crt"
+	certKeyPath    = "/etc/control-plane/certs/tls.key"
+	jwtKeysDir     = "/etc/control-plane-jwt"
 )
 
 var (
@@ -39,7 +45,6 @@ var (
 	enableJWT = false
 	protocol  = "http"
 	port      = httpPort
-	jwtToken  = ""
 )
 
 // GetEnableTLS returns whether TLS is enabled for the control plane server
@@ -62,9 +67,9 @@ func GetPort() string {
 	return port
 }
 
-// GetJWTToken returns the JWT token for the control plane server
-func GetJWTToken() string {
-	return jwtToken
+// GetServiceURL returns the in-cluster URL of the control plane server
+func GetServiceURL() string {
+	return fmt.Sprintf("%s://%s.%s.svc.cluster.local%s", protocol, ServiceName, k8sutils.GetOperatorNamespace(), port)
 }
 
 // setTLSConfig is an internal function to set TLS configuration
@@ -78,7 +83,9 @@ func setTLSConfig(enabled bool, proto, p string) {
 // The port is automatically selected based on enableTLS flag:
 // - :9090 for HTTP (when TLS is disabled)
 // - :9443 for HTTPS (when TLS is enabled)
-func NewControlPlaneServer(enableTLSFlag bool, enableJWTFlag bool, logger *slog.Logger, nclient client.Client, config *rest.Config, scheme *runtime.Scheme) (*ControlPlaneServer, error) {
+// The tokens issued when JWT is enabled expire after jwtTTL.
+// With bootstrapFlag, the certificate and the JWT keys of the enabled features are generated when missing.
+func NewControlPlaneServer(enableTLSFlag bool, enableJWTFlag bool, bootstrapFlag bool, jwtTTL time.Duration, logger *slog.Logger, nclient client.Client, config *rest.Config, scheme *runtime.Scheme) (*ControlPlaneServer, error) {
 	// Select port based on TLS setting
 	addr := httpPort
 	if enableTLSFlag {
@@ -86,6 +93,9 @@ func NewControlPlaneServer(enableTLSFlag bool, enableJWTFlag bool, logger *slog.
 		addr = httpsPort
 	}
 	enableJWT = enableJWTFlag
+	if jwtTTL > 0 {
+		tokenTTL = jwtTTL
+	}
 	logger = logger.With("component", controllerName)
 
 	// Create kubernetes clientset for direct client-go operations
@@ -94,16 +104,28 @@ func NewControlPlaneServer(enableTLSFlag bool, enableJWTFlag bool, logger *slog.
 		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
 	}
 
+	if bootstrapFlag && (enableTLSFlag || enableJWTFlag) {
+		if err := bootstrapSecrets(context.Background(), kclient, enableTLSFlag, enableJWTFlag, logger); err != nil {
+			return nil, fmt.Errorf("failed to bootstrap control plane secrets: %w", err)
+		}
+	}
+
 	mux := http.NewServeMux()
 
 	cps := &ControlPlaneServer{
-		mux:     mux,
-		logger:  logger,
-		nclient: nclient,
-		kclient: kclient,
-		scheme:  scheme,
+		mux:         mux,
+		logger:      logger,
+		nclient:     nclient,
+		kclient:     kclient,
+		scheme:      scheme,
+		generations: NewGenerationStore(kclient),
+		nodes:       NewNodeRegistry(kclient),
+		specs:       NewComputeSpecBuilder(nclient, kclient),
 	}
 
+	cps.registerUpcallRoutes()
+	cps.registerComputeRoutes()
+
 	// Configure HTTP server
 	cps.server = &http.Server{
 		Addr:    addr,
@@ -134,17 +156,10 @@ func NewControlPlaneServer(enableTLSFlag bool, enableJWTFlag bool, logger *slog.
 			logger.Warn("failed to initialize JWT manager, disabling JWT authentication", "error", err)
 			enableJWT = false
 		} else {
-			// Store the JWT manager in the server instance
+			// Store the JWT manager in the server instance, it issues the scoped tokens
 			cps.jwtManager = jwtMgr
-			// Generate JWT token with no expiry
-			token, err := jwtMgr.GenerateToken("control-plane", nil)
-			if err != nil {
-				logger.Warn("failed to generate JWT token, disabling JWT authentication", "error", err)
-				enableJWT = false
-			} else {
-				jwtToken = token
-				logger.Info("JWT authentication enabled for control plane server")
-			}
+			issuer = jwtMgr
+			logger.Info("JWT authentication enabled for control plane server", "tokenTTL", tokenTTL)
 		}
 	}
 
@@ -0,0 +1,302 @@
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+	pageserverapi "github.com/stateless-pg/stateless-pg/pkg/pageserver-api"
+)
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	// generationsConfigMapSuffix is appended to the NeonCluster name to build the generation ConfigMap name
+	generationsConfigMapSuffix = "-generations"
+	// generationsDataKey is the ConfigMap key holding the JSON encoded tenant shard generations
+	generationsDataKey = "generations.json"
+	// generationsComponentLabel labels every generation ConfigMap so they can be listed across namespaces
+	generationsComponentLabel = "generations"
+	// DetachedNodeID is the node ID recorded for tenant shards that are not attached to any pageserver
+	DetachedNodeID uint64 = 0
+)
+
+// ShardGeneration is the durable attachment state of a single tenant shard.
+type ShardGeneration struct {
+	NodeID     uint64 `json:"nodeId"`
+	Generation uint32 `json:"generation"`
+}
+
+// GenerationStore persists tenant shard generations in one ConfigMap per NeonCluster,
+// so that generations survive operator restarts and leader changes.
+//
+// Generation ConfigMaps are intentionally not owned by their NeonCluster: generation numbers
+// must never go backwards for data that is still present in remote storage.
+type GenerationStore struct {
+	kclient kubernetes.Interface
+}
+
+// NewGenerationStore creates a new generation store backed by ConfigMaps.
+func NewGenerationStore(kclient kubernetes.Interface) *GenerationStore {
+	return &GenerationStore{
+		kclient: kclient,
+	}
+}
+
+// GenerationsConfigMapName returns the name of the generation ConfigMap of a NeonCluster.
+func GenerationsConfigMapName(neonClusterName string) string {
+	return neonClusterName + generationsConfigMapSuffix
+}
+
+// Attach attaches the tenant shard to the given node and returns the new generation.
+// The generation is incremented on every attach so that a previous attachment can
+// never be mistaken for the current one.
+func (s *GenerationStore) Attach(ctx context.Context, cluster types.NamespacedName, tenantShardID string, nodeID uint64) (uint32, error) {
+	var gen uint32
+	err := s.update(ctx, cluster, func(shards map[string]*ShardGeneration) bool {
+		shard, ok := shards[tenantShardID]
+		if !ok {
+			shard = &ShardGeneration{}
+			shards[tenantShardID] = shard
+		}
+		shard.NodeID = nodeID
+		shard.Generation++
+		gen = shard.Generation
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return gen, nil
+}
+
+// Detach marks the tenant shard as not attached to any pageserver.
+// The generation is kept so that a later attach continues from it.
+func (s *GenerationStore) Detach(ctx context.Context, cluster types.NamespacedName, tenantShardID string) error {
+	return s.update(ctx, cluster, func(shards map[string]*ShardGeneration) bool {
+		shard, ok := shards[tenantShardID]
+		if !ok || shard.NodeID == DetachedNodeID {
+			return false
+		}
+		shard.NodeID = DetachedNodeID
+		return true
+	})
+}
+
+// Delete removes the generations of a NeonCluster.
+// It must only be called once the remote data of the tenants is gone, see GenerationStore.
+func (s *GenerationStore) Delete(ctx context.Context, cluster types.NamespacedName) error {
+	err := s.kclient.CoreV1().ConfigMaps(cluster.Namespace).Delete(ctx, GenerationsConfigMapName(cluster.Name), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete generation configmap: %w", err)
+	}
+	return nil
+}
+
+// Get returns the attachment state of a tenant shard, or nil if it was never attached.
+func (s *GenerationStore) Get(ctx context.Context, cluster types.NamespacedName, tenantShardID string) (*ShardGeneration, error) {
+	cm, err := s.kclient.CoreV1().ConfigMaps(cluster.Namespace).Get(ctx, GenerationsConfigMapName(cluster.Name), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get generation configmap: %w", err)
+	}
+
+	shards, err := decodeGenerations(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	return shards[tenantShardID], nil
+}
+
+// ReAttach increments the generation of every tenant shard attached to the given node
+// and returns them. It is called when a pageserver restarts.
+func (s *GenerationStore) ReAttach(ctx context.Context, nodeID uint64) ([]ReAttachResponseTenant, error) {
+	clusters, err := s.listClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenants := make([]ReAttachResponseTenant, 0)
+	for _, cluster := range clusters {
+		var attached []ReAttachResponseTenant
+		err := s.update(ctx, cluster, func(shards map[string]*ShardGeneration) bool {
+			// Reset on every attempt, a conflicting write retries the whole mutation
+			attached = attached[:0]
+			for id, shard := range shards {
+				if shard.NodeID != nodeID || nodeID == DetachedNodeID {
+					continue
+				}
+				shard.Generation++
+				gen := shard.Generation
+				attached = append(attached, ReAttachResponseTenant{
+					ID:         id,
+					Generation: &gen,
+					Mode:       pageserverapi.LocationConfigModeAttachedSingle,
+					StripeSize: pageserverapi.DefaultStripeSize,
+					Config:     map[string]interface{}{},
+				})
+			}
+			return len(attached) > 0
+		})
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, attached...)
+	}
+
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].ID < tenants[j].ID
+	})
+
+	return tenants, nil
+}
+
+// Validate reports for each tenant shard whether the given generation is still the latest one.
+// Tenant shards unknown to the store are never valid. The request does not identify the cluster of the
+// pageserver, a tenant shard found in the generations of several clusters is ambiguous and never valid either.
+func (s *GenerationStore) Validate(ctx context.Context, tenants []ValidateRequestTenant) ([]ValidateResponseTenant, error) {
+	clusters, err := s.listClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]uint32)
+	ambiguous := make(map[string]bool)
+	for _, cluster := range clusters {
+		cm, err := s.kclient.CoreV1().ConfigMaps(cluster.Namespace).Get(ctx, GenerationsConfigMapName(cluster.Name), metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get generation configmap: %w", err)
+		}
+
+		shards, err := decodeGenerations(cm)
+		if err != nil {
+			return nil, err
+		}
+		for id, shard := range shards {
+			if _, ok := latest[id]; ok {
+				ambiguous[id] = true
+			}
+			latest[id] = shard.Generation
+		}
+	}
+
+	result := make([]ValidateResponseTenant, 0, len(tenants))
+	for _, t := range tenants {
+		gen, ok := latest[t.ID]
+		result = append(result, ValidateResponseTenant{
+			ID:    t.ID,
+			Valid: ok && !ambiguous[t.ID] && gen == t.Generation,
+		})
+	}
+
+	return result, nil
+}
+
+// listClusters returns the NeonClusters that have a generation ConfigMap
+func (s *GenerationStore) listClusters(ctx context.Context) ([]types.NamespacedName, error) {
+	cms, err := s.kclient.CoreV1().ConfigMaps(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("component=%s", generationsComponentLabel),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list generation configmaps: %w", err)
+	}
+
+	clusters := make([]types.NamespacedName, 0, len(cms.Items))
+	for _, cm := range cms.Items {
+		clusters = append(clusters, types.NamespacedName{
+			Name:      cm.Labels["neoncluster"],
+			Namespace: cm.Namespace,
+		})
+	}
+
+	return clusters, nil
+}
+
+// update applies mutate to the generations of a NeonCluster and persists the result.
+// mutate returns whether it changed anything. Conflicting writes are retried.
+func (s *GenerationStore) update(ctx context.Context, cluster types.NamespacedName, mutate func(map[string]*ShardGeneration) bool) error {
+	name := GenerationsConfigMapName(cluster.Name)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := s.kclient.CoreV1().ConfigMaps(cluster.Namespace).Get(ctx, name, metav1.GetOptions{})
+		notFound := apierrors.IsNotFound(err)
+		if err != nil && !notFound {
+			return fmt.Errorf("failed to get generation configmap: %w", err)
+		}
+
+		if notFound {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: cluster.Namespace,
+				},
+			}
+			operator.UpdateObject(cm,
+				operator.WithLabels(map[string]string{
+					"neoncluster": cluster.Name,
+					"component":   generationsComponentLabel,
+				}),
+			)
+		}
+
+		shards, err := decodeGenerations(cm)
+		if err != nil {
+			return err
+		}
+
+		if !mutate(shards) {
+			return nil
+		}
+
+		data, err := json.Marshal(shards)
+		if err != nil {
+			return fmt.Errorf("failed to encode generations: %w", err)
+		}
+		cm.Data = map[string]string{
+			generationsDataKey: string(data),
+		}
+
+		if notFound {
+			_, err = s.kclient.CoreV1().ConfigMaps(cluster.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Lost a race with another writer, retry against the stored object
+				return apierrors.NewConflict(corev1.Resource("configmaps"), name, err)
+			}
+		} else {
+			_, err = s.kclient.CoreV1().ConfigMaps(cluster.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		}
+		return err
+	})
+}
+
+// decodeGenerations decodes the tenant shard generations stored in a ConfigMap
+func decodeGenerations(cm *corev1.ConfigMap) (map[string]*ShardGeneration, error) {
+	shards := make(map[string]*ShardGeneration)
+
+	data, ok := cm.Data[generationsDataKey]
+	if !ok || data == "" {
+		return shards, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &shards); err != nil {
+		return nil, fmt.Errorf("failed to decode generations in %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+
+	return shards, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGenerationStoreValidate(t *testing.T) {
+	ctx := context.Background()
+	store := NewGenerationStore(fake.NewClientset())
+
+	first := types.NamespacedName{Name: "first", Namespace: "neon"}
+	second := types.NamespacedName{Name: "second", Namespace: "other"}
+	attach := func(cluster types.NamespacedName, tenantShardID string, nodeID uint64) uint32 {
+		gen, err := store.Attach(ctx, cluster, tenantShardID, nodeID)
+		if err != nil {
+			t.Fatalf("Attach() error = %v", err)
+		}
+		return gen
+	}
+
+	attach(first, "tenant-a", 1)
+	attach(first, "tenant-a", 2)
+	attach(first, "tenant-b", 1)
+	attach(second, "tenant-b", 3)
+	attach(second, "tenant-c", 3)
+
+	got, err := store.Validate(ctx, []ValidateRequestTenant{
+		{ID: "tenant-a", Generation: 2},
+		{ID: "tenant-a", Generation: 1},
+		// Attached in both clusters with the same generation
+		{ID: "tenant-b", Generation: 1},
+		{ID: "tenant-c", Generation: 1},
+		{ID: "tenant-d", Generation: 1},
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	want := []ValidateResponseTenant{
+		{ID: "tenant-a", Valid: true},
+		{ID: "tenant-a", Valid: false},
+		{ID: "tenant-b", Valid: false},
+		{ID: "tenant-c", Valid: true},
+		{ID: "tenant-d", Valid: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %+v, want %+v", got, want)
+	}
+}
@@ -18,18 +18,44 @@ package controlplane
 
 import (
 	"crypto/rsa"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/big"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// JWTManager handles JWT token generation and verification
+const (
+	// activeKeyIDFile holds the key ID of the signing key in the JWT keys directory.
+	// Keys are stored as <kid>.pub and <kid>.key, the jwt key pair of older installs has the key ID jwt.
+	activeKeyIDFile = "active-kid"
+	// legacyKeyID is the key ID of the signing key when activeKeyIDFile is missing
+	legacyKeyID = "jwt"
+
+	publicKeySuffix  = ".pub"
+	privateKeySuffix = ".key"
+
+	// keyReloadInterval is how often the keys are read again, the mounted Secret changes during a rotation
+	keyReloadInterval = 30 * time.Second
+)
+
+// JWTManager handles JWT token generation and verification.
+// Tokens are signed with the active key and carry its ID in the kid header, any published key verifies them.
 type JWTManager struct {
-	publicKey  *rsa.PublicKey
-	privateKey *rsa.PrivateKey
-	logger     *slog.Logger
+	mu          sync.RWMutex
+	publicKeys  map[string]*rsa.PublicKey
+	privateKey  *rsa.PrivateKey
+	activeKeyID string
+	loadedAt    time.Time
+	logger      *slog.Logger
 }
 
 // NewJWTManager creates a new JWT manager and loads keys from files
@@ -38,27 +64,129 @@ func NewJWTManager(logger *slog.Logger) (*JWTManager, error) {
 		logger: logger,
 	}
 
-	// Load public key
-	publicKey, err := loadPublicKey()
+	if err := jm.load(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("JWT manager initialized successfully", "activeKeyID", jm.activeKeyID, "keyIDs", jm.KeyIDs())
+	return jm, nil
+}
+
+// load reads the public keys, the active key ID and its private key from the keys directory
+func (jm *JWTManager) load() error {
+	entries, err := os.ReadDir(jwtKeysDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load JWT public key: %w", err)
+		return fmt.Errorf("failed to read JWT keys directory: %w", err)
 	}
-	jm.publicKey = publicKey
 
-	// Load private key
-	privateKey, err := loadPrivateKey()
+	publicKeys := map[string]*rsa.PublicKey{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") || !strings.HasSuffix(name, publicKeySuffix) {
+			continue
+		}
+		publicKey, err := loadPublicKey(filepath.Join(jwtKeysDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to load JWT public key %s: %w", name, err)
+		}
+		publicKeys[strings.TrimSuffix(name, publicKeySuffix)] = publicKey
+	}
+
+	activeKeyID := legacyKeyID
+	if data, err := os.ReadFile(filepath.Join(jwtKeysDir, activeKeyIDFile)); err == nil {
+		activeKeyID = strings.TrimSpace(string(data))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read active JWT key ID: %w", err)
+	}
+
+	if _, ok := publicKeys[activeKeyID]; !ok {
+		return fmt.Errorf("public key of the active JWT key %s not found", activeKeyID)
+	}
+	privateKey, err := loadPrivateKey(filepath.Join(jwtKeysDir, activeKeyID+privateKeySuffix))
 	if err != nil {
-		return nil, fmt.Errorf("failed to load JWT private key: %w", err)
+		return fmt.Errorf("failed to load JWT private key %s: %w", activeKeyID, err)
 	}
+
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	if jm.activeKeyID != "" && jm.activeKeyID != activeKeyID {
+		jm.logger.Info("JWT signing key changed", "previous", jm.activeKeyID, "activeKeyID", activeKeyID)
+	}
+	jm.publicKeys = publicKeys
 	jm.privateKey = privateKey
+	jm.activeKeyID = activeKeyID
+	jm.loadedAt = time.Now()
 
-	logger.Info("JWT manager initialized successfully")
-	return jm, nil
+	return nil
+}
+
+// reload reads the keys again when they were loaded more than keyReloadInterval ago.
+// The last keys are kept if they cannot be read, a Secret update may be in flight.
+func (jm *JWTManager) reload() {
+	jm.mu.RLock()
+	fresh := time.Since(jm.loadedAt) < keyReloadInterval
+	jm.mu.RUnlock()
+	if fresh {
+		return
+	}
+
+	if err := jm.load(); err != nil {
+		jm.logger.Warn("failed to reload JWT keys, keeping the current keys", "error", err)
+		jm.mu.Lock()
+		jm.loadedAt = time.Now()
+		jm.mu.Unlock()
+	}
+}
+
+// ActiveKeyID returns the ID of the key tokens are signed with
+func (jm *JWTManager) ActiveKeyID() string {
+	jm.reload()
+
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	return jm.activeKeyID
+}
+
+// KeyIDs returns the sorted IDs of the keys tokens are verified with
+func (jm *JWTManager) KeyIDs() []string {
+	jm.reload()
+
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	ids := make([]string, 0, len(jm.publicKeys))
+	for id := range jm.publicKeys {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
 }
 
-// loadPublicKey loads the RSA public key from file
-func loadPublicKey() (*rsa.PublicKey, error) {
-	pubKeyData, err := os.ReadFile(jwtPublicKeyPath)
+// JWKS returns the public keys tokens are verified with as JSON Web Keys, sorted by key ID
+func (jm *JWTManager) JWKS() []map[string]interface{} {
+	jm.reload()
+
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	keys := make([]map[string]interface{}, 0, len(jm.publicKeys))
+	for id, publicKey := range jm.publicKeys {
+		keys = append(keys, map[string]interface{}{
+			"kty": "RSA",
+			"kid": id,
+			"alg": jwt.SigningMethodRS256.Alg(),
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		})
+	}
+	slices.SortFunc(keys, func(a, b map[string]interface{}) int {
+		return strings.Compare(a["kid"].(string), b["kid"].(string))
+	})
+	return keys
+}
+
+// loadPublicKey loads an RSA public key from file
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	pubKeyData, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read public key file: %w", err)
 	}
@@ -71,9 +199,9 @@ func loadPublicKey() (*rsa.PublicKey, error) {
 	return pubKey, nil
 }
 
-// loadPrivateKey loads the RSA private key from file
-func loadPrivateKey() (*rsa.PrivateKey, error) {
-	privKeyData, err := os.ReadFile(jwtPrivateKeyPath)
+// loadPrivateKey loads an RSA private key from file
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	privKeyData, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read private key file: %w", err)
 	}
@@ -86,35 +214,101 @@ func loadPrivateKey() (*rsa.PrivateKey, error) {
 	return privKey, nil
 }
 
-// TokenClaims represents the JWT claims
+// Scope is the scope claim of a token, checked by the Neon components against the API called
+type Scope string
+
+const (
+	// ScopeTenant grants access to the data of the tenant of the tenant_id claim, used by computes
+	ScopeTenant Scope = "tenant"
+	// ScopePageServerAPI grants access to the pageserver management API
+	ScopePageServerAPI Scope = "pageserverapi"
+	// ScopeSafekeeperData grants access to the safekeeper API and WAL, used by safekeepers between them
+	ScopeSafekeeperData Scope = "safekeeperdata"
+	// ScopeGenerationsAPI grants access to the control plane upcall API, used by pageservers
+	ScopeGenerationsAPI Scope = "generations_api"
+	// ScopeCompute grants access to the spec of the compute of the subject, used by compute_ctl
+	ScopeCompute Scope = "compute"
+	// ScopeAdmin grants access to every control plane route
+	ScopeAdmin Scope = "admin"
+)
+
+// ComputeAudience is the audience of the tokens of the compute_ctl API
+const ComputeAudience = "compute"
+
+// TokenClaims represents the JWT claims, in the format of the Neon components.
+// compute_ctl tokens have no scope, they carry the compute_id of the compute they grant.
 type TokenClaims struct {
 	jwt.RegisteredClaims
-	Subject string
+	Scope     Scope  `json:"scope,omitempty"`
+	TenantID  string `json:"tenant_id,omitempty"`
+	ComputeID string `json:"compute_id,omitempty"`
+}
+
+// GenerateToken generates a new JWT token for a subject with a scope, expiring after ttl.
+// tenantID is only set on tenant scoped tokens.
+func (jm *JWTManager) GenerateToken(subject string, scope Scope, tenantID string, ttl time.Duration) (string, time.Time, error) {
+	jm.reload()
+
+	now := time.Now()
+	expiresAt := now.Add(ttl).Truncate(time.Second)
+	claims := TokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Scope:    scope,
+		TenantID: tenantID,
+	}
+
+	tokenString, err := jm.sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	jm.logger.Debug("JWT token generated", "subject", subject, "scope", scope, "expiresAt", expiresAt)
+	return tokenString, expiresAt, nil
 }
 
-// GenerateToken generates a new JWT token with no expiry
-func (jm *JWTManager) GenerateToken(subject string, customClaims map[string]interface{}) (string, error) {
+// GenerateComputeToken generates a token of the compute_ctl API of a compute, expiring after ttl.
+// compute_ctl verifies it with the keys of the spec and checks the compute_id claim against its own ID.
+func (jm *JWTManager) GenerateComputeToken(subject, computeID string, ttl time.Duration) (string, error) {
+	jm.reload()
+
+	now := time.Now()
 	claims := TokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			Subject: subject,
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{ComputeAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
-		Subject: subject,
+		ComputeID: computeID,
 	}
 
+	return jm.sign(claims)
+}
+
+// sign signs the claims with the active private key, the token carries its ID in the kid header
+func (jm *JWTManager) sign(claims TokenClaims) (string, error) {
+	jm.mu.RLock()
+	privateKey, keyID := jm.privateKey, jm.activeKeyID
+	jm.mu.RUnlock()
+
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
 
-	// Sign the token with the private key
-	tokenString, err := token.SignedString(jm.privateKey)
+	tokenString, err := token.SignedString(privateKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
-
-	jm.logger.Info("JWT token generated", "subject", subject)
 	return tokenString, nil
 }
 
-// VerifyToken verifies a JWT token and returns the claims
+// VerifyToken verifies a JWT token and returns the claims.
+// Tokens with a kid header are verified with that key, tokens without one with any published key.
 func (jm *JWTManager) VerifyToken(tokenString string) (*TokenClaims, error) {
+	jm.reload()
 	claims := &TokenClaims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
@@ -122,7 +316,23 @@ func (jm *JWTManager) VerifyToken(tokenString string) (*TokenClaims, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return jm.publicKey, nil
+
+		jm.mu.RLock()
+		defer jm.mu.RUnlock()
+
+		if kid, ok := token.Header["kid"].(string); ok {
+			publicKey, found := jm.publicKeys[kid]
+			if !found {
+				return nil, fmt.Errorf("unknown key ID %q", kid)
+			}
+			return publicKey, nil
+		}
+
+		keys := jwt.VerificationKeySet{}
+		for _, publicKey := range jm.publicKeys {
+			keys.Keys = append(keys.Keys, publicKey)
+		}
+		return keys, nil
 	})
 
 	if err != nil {
@@ -135,3 +345,13 @@ func (jm *JWTManager) VerifyToken(tokenString string) (*TokenClaims, error) {
 
 	return claims, nil
 }
+
+// tokenKeyID returns the kid header of a token, without verifying it
+func tokenKeyID(tokenString string) string {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &TokenClaims{})
+	if err != nil {
+		return ""
+	}
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
@@ -0,0 +1,274 @@
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+const (
+	// nodeRegistryConfigMapName is the name of the node registry ConfigMap in the operator namespace
+	nodeRegistryConfigMapName = "pageserver-nodes"
+	// nodeRegistryDataKey is the ConfigMap key holding the JSON encoded node registry
+	nodeRegistryDataKey = "nodes.json"
+	// firstNodeID is the first node ID handed out, 0 is DetachedNodeID
+	firstNodeID uint64 = 1
+)
+
+// RegisteredNode is a pageserver node known to the control plane.
+type RegisteredNode struct {
+	NodeID         uint64 `json:"nodeId"`
+	Namespace      string `json:"namespace"`
+	PageServer     string `json:"pageServer"`
+	Ordinal        int32  `json:"ordinal"`
+	ListenPgAddr   string `json:"listenPgAddr"`
+	ListenHTTPAddr string `json:"listenHttpAddr"`
+}
+
+// PodName returns the name of the StatefulSet pod running the node.
+func (n *RegisteredNode) PodName() string {
+	return fmt.Sprintf("%s-%d", n.PageServer, n.Ordinal)
+}
+
+// nodeRegistryData is the content of the node registry ConfigMap
+type nodeRegistryData struct {
+	NextNodeID uint64                     `json:"nextNodeId"`
+	Nodes      map[string]*RegisteredNode `json:"nodes"`
+}
+
+// NodeRegistry hands out stable numeric node IDs to pageserver pods.
+// IDs are keyed by PageServer and StatefulSet ordinal so that a restarted or rescheduled pod
+// gets its previous ID back. They are unique across all clusters, because upcalls such as
+// re-attach only carry the node ID.
+//
+// The registry lives in a single ConfigMap in the operator namespace. IDs of removed ordinals
+// are kept and never handed to another pod: generations recorded for a node ID must keep
+// pointing at the same node.
+type NodeRegistry struct {
+	kclient kubernetes.Interface
+}
+
+// NewNodeRegistry creates a new node registry backed by a ConfigMap.
+func NewNodeRegistry(kclient kubernetes.Interface) *NodeRegistry {
+	return &NodeRegistry{
+		kclient: kclient,
+	}
+}
+
+// Allocate makes sure the ordinals 0..replicas-1 of the PageServer have a node ID and returns
+// their nodes ordered by ordinal. addrs returns the pg and http listen addresses of an ordinal.
+func (r *NodeRegistry) Allocate(ctx context.Context, pageserver types.NamespacedName, replicas int32, addrs func(ordinal int32) (string, string)) ([]RegisteredNode, error) {
+	var nodes []RegisteredNode
+	err := r.update(ctx, func(data *nodeRegistryData) bool {
+		// Reset on every attempt, a conflicting write retries the whole mutation
+		nodes = nodes[:0]
+		changed := false
+		for ordinal := int32(0); ordinal < replicas; ordinal++ {
+			pgAddr, httpAddr := addrs(ordinal)
+			key := nodeKey(pageserver, ordinal)
+			node, ok := data.Nodes[key]
+			if !ok {
+				node = &RegisteredNode{
+					NodeID:     data.NextNodeID,
+					Namespace:  pageserver.Namespace,
+					PageServer: pageserver.Name,
+					Ordinal:    ordinal,
+				}
+				data.Nodes[key] = node
+				data.NextNodeID++
+				changed = true
+			}
+			if node.ListenPgAddr != pgAddr || node.ListenHTTPAddr != httpAddr {
+				node.ListenPgAddr = pgAddr
+				node.ListenHTTPAddr = httpAddr
+				changed = true
+			}
+			nodes = append(nodes, *node)
+		}
+		return changed
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// Register records the listen addresses a pageserver reports for itself.
+// Node IDs unknown to the registry are rejected, IDs are only handed out by Allocate.
+func (r *NodeRegistry) Register(ctx context.Context, nodeID uint64, pgAddr, httpAddr string) error {
+	found := false
+	err := r.update(ctx, func(data *nodeRegistryData) bool {
+		found = false
+		for _, node := range data.Nodes {
+			if node.NodeID != nodeID {
+				continue
+			}
+			found = true
+			if node.ListenPgAddr == pgAddr && node.ListenHTTPAddr == httpAddr {
+				return false
+			}
+			node.ListenPgAddr = pgAddr
+			node.ListenHTTPAddr = httpAddr
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("node %d is not registered", nodeID)
+	}
+
+	return nil
+}
+
+// Get returns the node with the given ID, or nil if it is not registered.
+func (r *NodeRegistry) Get(ctx context.Context, nodeID uint64) (*RegisteredNode, error) {
+	cm, err := r.kclient.CoreV1().ConfigMaps(k8sutils.GetOperatorNamespace()).Get(ctx, nodeRegistryConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get node registry configmap: %w", err)
+	}
+
+	data, err := decodeNodeRegistry(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range data.Nodes {
+		if node.NodeID == nodeID {
+			return node, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// List returns the registered nodes of a PageServer ordered by ordinal, including removed ordinals.
+func (r *NodeRegistry) List(ctx context.Context, pageserver types.NamespacedName) ([]RegisteredNode, error) {
+	cm, err := r.kclient.CoreV1().ConfigMaps(k8sutils.GetOperatorNamespace()).Get(ctx, nodeRegistryConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get node registry configmap: %w", err)
+	}
+
+	data, err := decodeNodeRegistry(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]RegisteredNode, 0)
+	for _, node := range data.Nodes {
+		if node.Namespace == pageserver.Namespace && node.PageServer == pageserver.Name {
+			nodes = append(nodes, *node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Ordinal < nodes[j].Ordinal
+	})
+
+	return nodes, nil
+}
+
+// update applies mutate to the node registry and persists the result.
+// mutate returns whether it changed anything. Conflicting writes are retried.
+func (r *NodeRegistry) update(ctx context.Context, mutate func(*nodeRegistryData) bool) error {
+	namespace := k8sutils.GetOperatorNamespace()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := r.kclient.CoreV1().ConfigMaps(namespace).Get(ctx, nodeRegistryConfigMapName, metav1.GetOptions{})
+		notFound := apierrors.IsNotFound(err)
+		if err != nil && !notFound {
+			return fmt.Errorf("failed to get node registry configmap: %w", err)
+		}
+
+		if notFound {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      nodeRegistryConfigMapName,
+					Namespace: namespace,
+				},
+			}
+			operator.UpdateObject(cm,
+				operator.WithLabels(map[string]string{
+					"component": "node-registry",
+				}),
+			)
+		}
+
+		data, err := decodeNodeRegistry(cm)
+		if err != nil {
+			return err
+		}
+
+		if !mutate(data) {
+			return nil
+		}
+
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to encode node registry: %w", err)
+		}
+		cm.Data = map[string]string{
+			nodeRegistryDataKey: string(encoded),
+		}
+
+		if notFound {
+			_, err = r.kclient.CoreV1().ConfigMaps(namespace).Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Lost a race with another writer, retry against the stored object
+				return apierrors.NewConflict(corev1.Resource("configmaps"), nodeRegistryConfigMapName, err)
+			}
+		} else {
+			_, err = r.kclient.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		}
+		return err
+	})
+}
+
+// nodeKey returns the registry key of a PageServer ordinal
+func nodeKey(pageserver types.NamespacedName, ordinal int32) string {
+	return fmt.Sprintf("%s/%s/%d", pageserver.Namespace, pageserver.Name, ordinal)
+}
+
+// decodeNodeRegistry decodes the node registry stored in a ConfigMap
+func decodeNodeRegistry(cm *corev1.ConfigMap) (*nodeRegistryData, error) {
+	data := &nodeRegistryData{
+		NextNodeID: firstNodeID,
+		Nodes:      make(map[string]*RegisteredNode),
+	}
+
+	raw, ok := cm.Data[nodeRegistryDataKey]
+	if !ok || raw == "" {
+		return data, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), data); err != nil {
+		return nil, fmt.Errorf("failed to decode node registry in %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	if data.Nodes == nil {
+		data.Nodes = make(map[string]*RegisteredNode)
+	}
+	if data.NextNodeID < firstNodeID {
+		data.NextNodeID = firstNodeID
+	}
+
+	return data, nil
+}
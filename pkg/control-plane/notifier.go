@@ -0,0 +1,247 @@
+package controlplane
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=computeendpoints/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+const (
+	// computeHTTPPort is the port of the compute_ctl HTTP API
+	computeHTTPPort = 3080
+	// computeNotifyTimeout bounds a single /configure request
+	computeNotifyTimeout = 30 * time.Second
+	// computeTokenTTL is the lifetime of the tokens of the compute_ctl API, issued for every push
+	computeTokenTTL = 5 * time.Minute
+)
+
+// computeNotifyBackoff spaces out /configure retries, a compute that just restarted needs a few seconds
+var computeNotifyBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 500 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// ComputeConfigureRequest is the body of POST /configure on compute_ctl.
+type ComputeConfigureRequest struct {
+	Spec             *ComputeSpec     `json:"spec"`
+	ComputeCtlConfig ComputeCtlConfig `json:"compute_ctl_config"`
+}
+
+// ComputeNotifier pushes spec changes to running computes, the equivalent of the
+// storage controller notify-attach hook. It is called when a tenant moves to another pageserver.
+type ComputeNotifier struct {
+	nclient    client.Client
+	kclient    kubernetes.Interface
+	specs      *ComputeSpecBuilder
+	logger     *slog.Logger
+	httpClient *http.Client
+}
+
+// NewComputeNotifier creates a new compute notifier.
+func NewComputeNotifier(nclient client.Client, kclient kubernetes.Interface, logger *slog.Logger) *ComputeNotifier {
+	return &ComputeNotifier{
+		nclient: nclient,
+		kclient: kclient,
+		specs:   NewComputeSpecBuilder(nclient, kclient),
+		logger:  logger,
+		httpClient: &http.Client{
+			Timeout: computeNotifyTimeout,
+		},
+	}
+}
+
+// NotifyTenant pushes the spec to every compute running on a timeline of the tenant.
+// tn must carry the new attachment, the cached Tenant may not have it yet.
+// Every compute is notified even if some fail, the returned error joins all failures.
+func (n *ComputeNotifier) NotifyTenant(ctx context.Context, tn *v1alpha1.Tenant) error {
+	timelines := &v1alpha1.TimelineList{}
+	if err := n.nclient.List(ctx, timelines, client.InNamespace(tn.Namespace)); err != nil {
+		return fmt.Errorf("failed to list timelines: %w", err)
+	}
+
+	tenantTimelines := make(map[string]*v1alpha1.Timeline)
+	for i := range timelines.Items {
+		if timelines.Items[i].Spec.TenantRef.Name == tn.Name {
+			tenantTimelines[timelines.Items[i].Name] = &timelines.Items[i]
+		}
+	}
+	if len(tenantTimelines) == 0 {
+		return nil
+	}
+
+	endpoints := &v1alpha1.ComputeEndpointList{}
+	if err := n.nclient.List(ctx, endpoints, client.InNamespace(tn.Namespace)); err != nil {
+		return fmt.Errorf("failed to list computeendpoints: %w", err)
+	}
+
+	var errs []error
+	for i := range endpoints.Items {
+		ep := &endpoints.Items[i]
+		tl, ok := tenantTimelines[ep.Spec.TimelineRef.Name]
+		if !ok || !meta.IsStatusConditionTrue(tl.Status.Conditions, v1alpha1.TimelineConditionReady) {
+			continue
+		}
+
+		spec, err := n.specs.build(ctx, ep, tl, tn)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("compute %s: failed to build compute spec: %w", ep.Name, err))
+			continue
+		}
+		if err := n.push(ctx, ep, spec); err != nil {
+			errs = append(errs, fmt.Errorf("compute %s: %w", ep.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Notify pushes the current spec of a ComputeEndpoint to its running pods.
+func (n *ComputeNotifier) Notify(ctx context.Context, ep *v1alpha1.ComputeEndpoint) error {
+	spec, err := n.specs.Build(ctx, ep)
+	if err != nil {
+		if errors.Is(err, ErrComputeNotReady) {
+			return nil
+		}
+		return fmt.Errorf("failed to build compute spec: %w", err)
+	}
+
+	return n.push(ctx, ep, spec)
+}
+
+// push sends the spec to the running pods of a ComputeEndpoint and records the outcome
+// in the Configured condition. Computes that are not running yet fetch the spec on startup.
+func (n *ComputeNotifier) push(ctx context.Context, ep *v1alpha1.ComputeEndpoint, spec *ComputeSpec) error {
+	logger := n.logger.With("key", fmt.Sprintf("%s/%s", ep.Namespace, ep.Name))
+
+	pods, err := n.kclient.CoreV1().Pods(ep.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", ep.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list compute pods: %w", err)
+	}
+
+	var errs []error
+	notified := 0
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		err := retry.OnError(computeNotifyBackoff, func(error) bool { return ctx.Err() == nil }, func() error {
+			return n.configure(ctx, pod.Status.PodIP, ComputeID(ep), spec)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pod %s: %w", pod.Name, err))
+			continue
+		}
+		notified++
+		logger.Info("notified compute", "pod", pod.Name, "pageserver", spec.PageserverConnstring)
+	}
+
+	cond := metav1.Condition{
+		Type:    v1alpha1.ComputeEndpointConditionConfigured,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Notified",
+		Message: fmt.Sprintf("spec pushed to %d compute pods", notified),
+	}
+	connstring := spec.PageserverConnstring
+	err = errors.Join(errs...)
+	if err != nil {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "NotifyFailed"
+		cond.Message = err.Error()
+		connstring = ""
+	} else if notified == 0 {
+		cond.Reason = "NotRunning"
+		cond.Message = "no running compute pods, the spec is fetched on startup"
+	}
+
+	if statusErr := n.recordStatus(ctx, ep, cond, connstring); statusErr != nil {
+		logger.Error("failed to record compute notification", "error", statusErr)
+	}
+
+	return err
+}
+
+// configure sends the spec to the compute_ctl /configure endpoint of a pod.
+// The request is signed with a token for the compute ID, verified with the keys of the compute spec.
+func (n *ComputeNotifier) configure(ctx context.Context, podIP, computeID string, spec *ComputeSpec) error {
+	data, err := json.Marshal(ComputeConfigureRequest{
+		Spec:             spec,
+		ComputeCtlConfig: ComputeCtlConfig{JWKS: publishedJWKS()},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode configure request: %w", err)
+	}
+
+	url := fmt.Sprintf("http://%s:%d/configure", podIP, computeHTTPPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create configure request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if GetEnableJWT() {
+		token, err := issueComputeToken(computeID)
+		if err != nil {
+			return fmt.Errorf("failed to issue compute token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST /configure failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("POST /configure returned %d: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
+
+// recordStatus stores the notification outcome on the ComputeEndpoint.
+// connstring is only recorded on success so that failed notifications are retried.
+func (n *ComputeNotifier) recordStatus(ctx context.Context, ep *v1alpha1.ComputeEndpoint, cond metav1.Condition, connstring string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &v1alpha1.ComputeEndpoint{}
+		if err := n.nclient.Get(ctx, client.ObjectKeyFromObject(ep), latest); err != nil {
+			return err
+		}
+
+		cond.ObservedGeneration = latest.Generation
+		meta.SetStatusCondition(&latest.Status.Conditions, cond)
+		latest.Status.ConfiguredPageServerConnstring = connstring
+		if cond.Status == metav1.ConditionTrue {
+			now := metav1.Now()
+			latest.Status.LastConfiguredTime = &now
+		}
+
+		return n.nclient.Status().Update(ctx, latest)
+	})
+}
@@ -0,0 +1,350 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+
+const (
+	// JWTKeysSecretName is the Secret of the operator namespace holding the JWT keys, mounted by the operator.
+	// Its public keys are copied to the namespace of every NeonCluster.
+	JWTKeysSecretName = "control-plane-jwt-keys"
+
+	// JWTKeyIDsAnnotationKey is the IDs of the public keys trusted by the pods of a component,
+	// set on their pod template so that they restart when the published keys change
+	JWTKeyIDsAnnotationKey = "neon.io/jwt-key-ids"
+
+	// JWTRotateAnnotationKey requests a rotation of the signing key when set on the JWT keys Secret
+	// to a value other than the one of the last rotation, e.g. a date
+	JWTRotateAnnotationKey = "neon.io/jwt-rotate"
+	// jwtRotatedAnnotation is the value of JWTRotateAnnotationKey of the last completed rotation
+	jwtRotatedAnnotation = "neon.io/jwt-rotated"
+	// jwtRotationPhaseAnnotation is the phase of the rotation in progress
+	jwtRotationPhaseAnnotation = "neon.io/jwt-rotation-phase"
+	// jwtRotationKeyIDAnnotation is the ID of the key introduced by the rotation in progress
+	jwtRotationKeyIDAnnotation = "neon.io/jwt-rotation-kid"
+	// jwtRotationPublishedAtAnnotation is when the rotation in progress published the new key
+	jwtRotationPublishedAtAnnotation = "neon.io/jwt-rotation-published-at"
+	// jwtRotationSwitchedAtAnnotation is when the rotation in progress switched the signing key
+	jwtRotationSwitchedAtAnnotation = "neon.io/jwt-rotation-switched-at"
+
+	// rotationPhasePublishing waits for every component to trust the new key
+	rotationPhasePublishing = "Publishing"
+	// rotationPhaseSwitching signs with the new key and waits for every token to be reissued with it
+	rotationPhaseSwitching = "Switching"
+
+	// tokenProjectionDelay is how long the kubelet may take to project an updated token Secret into the pods,
+	// its sync period and Secret cache TTL
+	tokenProjectionDelay = 2 * time.Minute
+
+	// rotationKeyBits is the size of the RSA keys generated by a rotation
+	rotationKeyBits = 2048
+)
+
+// rotateKeys advances the rotation of the signing key requested on the JWT keys Secret, one phase at a time:
+//   - a new key pair is added to the Secret, its public key is published to the components and computes;
+//   - once every component and compute trusts it, the new key becomes the signing key and every token is reissued;
+//   - once every component and compute runs with a token of the new key, the previous keys are retired.
+//
+// Tokens of the previous keys stay valid until they are retired, the rotation needs no downtime.
+// It returns the time the running computes must have been configured since while the rotation is in progress,
+// when the key was published or the signing key switched, the zero time otherwise.
+func (r *TokenRefresher) rotateKeys(ctx context.Context) (time.Time, error) {
+	secrets := r.kclient.CoreV1().Secrets(k8sutils.GetOperatorNamespace())
+	secret, err := secrets.Get(ctx, JWTKeysSecretName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get JWT keys secret: %w", err)
+	}
+
+	request := secret.Annotations[JWTRotateAnnotationKey]
+	keyID := secret.Annotations[jwtRotationKeyIDAnnotation]
+	logger := r.logger.With("rotation", request, "kid", keyID)
+
+	switch secret.Annotations[jwtRotationPhaseAnnotation] {
+	case "":
+		if request == "" || request == secret.Annotations[jwtRotatedAnnotation] {
+			return time.Time{}, nil
+		}
+		return time.Time{}, r.publishKey(ctx, secret)
+
+	case rotationPhasePublishing:
+		publishedAt, err := time.Parse(time.RFC3339, secret.Annotations[jwtRotationPublishedAtAnnotation])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s annotation: %w", jwtRotationPublishedAtAnnotation, err)
+		}
+
+		trusted, err := r.keyTrusted(ctx, keyID, publishedAt)
+		if err != nil || !trusted {
+			return publishedAt, err
+		}
+
+		switchedAt := time.Now().UTC().Truncate(time.Second)
+		secret.Data[activeKeyIDFile] = []byte(keyID)
+		secret.Annotations[jwtRotationPhaseAnnotation] = rotationPhaseSwitching
+		secret.Annotations[jwtRotationSwitchedAtAnnotation] = switchedAt.Format(time.RFC3339)
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return time.Time{}, fmt.Errorf("failed to switch JWT signing key: %w", err)
+		}
+		logger.Info("Every component trusts the new JWT key, switched the signing key")
+		return switchedAt, nil
+
+	case rotationPhaseSwitching:
+		switchedAt, err := time.Parse(time.RFC3339, secret.Annotations[jwtRotationSwitchedAtAnnotation])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s annotation: %w", jwtRotationSwitchedAtAnnotation, err)
+		}
+
+		reissued, err := r.tokensReissued(ctx, keyID, switchedAt)
+		if err != nil || !reissued {
+			return switchedAt, err
+		}
+
+		for key := range secret.Data {
+			id := strings.TrimSuffix(strings.TrimSuffix(key, publicKeySuffix), privateKeySuffix)
+			if id != key && id != keyID {
+				delete(secret.Data, key)
+			}
+		}
+		delete(secret.Annotations, jwtRotationPhaseAnnotation)
+		delete(secret.Annotations, jwtRotationKeyIDAnnotation)
+		delete(secret.Annotations, jwtRotationPublishedAtAnnotation)
+		delete(secret.Annotations, jwtRotationSwitchedAtAnnotation)
+		secret.Annotations[jwtRotatedAnnotation] = request
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return switchedAt, fmt.Errorf("failed to retire previous JWT keys: %w", err)
+		}
+		logger.Info("Every token is signed with the new JWT key, retired the previous keys")
+		return time.Time{}, nil
+
+	default:
+		return time.Time{}, fmt.Errorf("unknown JWT key rotation phase %q", secret.Annotations[jwtRotationPhaseAnnotation])
+	}
+}
+
+// publishKey adds a new key pair to the JWT keys Secret, the signing key is unchanged
+func (r *TokenRefresher) publishKey(ctx context.Context, secret *corev1.Secret) error {
+	privateKey, err := rsa.GenerateKey(rand.Reader, rotationKeyBits)
+	if err != nil {
+		return fmt.Errorf("failed to generate JWT key: %w", err)
+	}
+	privatePEM, publicPEM, err := EncodeKeyPair(privateKey)
+	if err != nil {
+		return err
+	}
+
+	keyID := newKeyID()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	if _, ok := secret.Data[activeKeyIDFile]; !ok {
+		// Pin the signing key of older installs before adding another key
+		secret.Data[activeKeyIDFile] = []byte(legacyKeyID)
+	}
+	secret.Data[keyID+privateKeySuffix] = privatePEM
+	secret.Data[keyID+publicKeySuffix] = publicPEM
+
+	operator.UpdateObject(secret, operator.WithAnnotations(map[string]string{
+		jwtRotationPhaseAnnotation:       rotationPhasePublishing,
+		jwtRotationKeyIDAnnotation:       keyID,
+		jwtRotationPublishedAtAnnotation: time.Now().UTC().Truncate(time.Second).Format(time.RFC3339),
+	}))
+
+	if _, err := r.kclient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to publish JWT key: %w", err)
+	}
+	r.logger.Info("Published a new JWT key", "rotation", secret.Annotations[JWTRotateAnnotationKey], "kid", keyID)
+	return nil
+}
+
+// keyTrusted reports whether the operator loaded the key, every component rolled out pods trusting it
+// and every running compute was configured with it since it was published at publishedAt
+func (r *TokenRefresher) keyTrusted(ctx context.Context, keyID string, publishedAt time.Time) (bool, error) {
+	if issuer == nil || !slices.Contains(issuer.KeyIDs(), keyID) {
+		return false, nil
+	}
+
+	statefulSets, err := r.kclient.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", operator.ManagedByLabelKey, operator.ManagedByLabelValue),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+
+	for i := range statefulSets.Items {
+		ss := &statefulSets.Items[i]
+		keyIDs, ok := ss.Spec.Template.Annotations[JWTKeyIDsAnnotationKey]
+		if !ok {
+			continue
+		}
+		if !slices.Contains(strings.Split(keyIDs, ","), keyID) || !operator.StatefulSetRolledOut(ss) {
+			return false, nil
+		}
+	}
+
+	return r.computesConfiguredSince(ctx, publishedAt)
+}
+
+// tokensReissued reports whether the operator signs with the key, every token Secret holds a token signed
+// with it long enough ago for the kubelet to project it into the pods, every component reading it on startup
+// rolled out pods with it and every running compute was configured since the switch
+func (r *TokenRefresher) tokensReissued(ctx context.Context, keyID string, switchedAt time.Time) (bool, error) {
+	if activeKeyID() != keyID {
+		return false, nil
+	}
+
+	secrets, err := r.kclient.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: tokenScopeLabel,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list token secrets: %w", err)
+	}
+	expiries := make(map[string]string, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		if secret.Annotations[tokenKeyIDAnnotation] != keyID {
+			return false, nil
+		}
+		expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[TokenExpiresAtAnnotationKey])
+		if err != nil || time.Since(expiresAt.Add(-tokenTTL)) < tokenProjectionDelay {
+			return false, nil
+		}
+		expiries[secret.Namespace+"/"+secret.Name] = secret.Annotations[TokenExpiresAtAnnotationKey]
+	}
+
+	// Components reading the token on startup annotate their pods with its expiry
+	statefulSets, err := r.kclient.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", operator.ManagedByLabelKey, operator.ManagedByLabelValue),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		ss := &statefulSets.Items[i]
+		expiresAt, ok := ss.Spec.Template.Annotations[TokenExpiresAtAnnotationKey]
+		if !ok || expiresAt == "" {
+			continue
+		}
+		if expiries[ss.Namespace+"/"+TokenSecretName(ss.Name)] != expiresAt || !operator.StatefulSetRolledOut(ss) {
+			return false, nil
+		}
+	}
+
+	return r.computesConfiguredSince(ctx, switchedAt)
+}
+
+// computesConfiguredSince reports whether every running compute was configured since the time,
+// with the published keys and a token of the signing key
+func (r *TokenRefresher) computesConfiguredSince(ctx context.Context, since time.Time) (bool, error) {
+	endpoints := &v1alpha1.ComputeEndpointList{}
+	if err := r.nclient.List(ctx, endpoints); err != nil {
+		return false, fmt.Errorf("failed to list compute endpoints: %w", err)
+	}
+	for _, ep := range endpoints.Items {
+		if !meta.IsStatusConditionTrue(ep.Status.Conditions, v1alpha1.ComputeEndpointConditionReady) {
+			continue
+		}
+		if ep.Status.LastConfiguredTime == nil || ep.Status.LastConfiguredTime.Time.Before(since) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// newKeyID returns the ID of a new key, the time it was generated
+func newKeyID() string {
+	return "k" + time.Now().UTC().Format("20060102150405")
+}
+
+// EncodeKeyPair returns the PEM encoding of an RSA private key and of its public key
+func EncodeKeyPair(privateKey *rsa.PrivateKey) ([]byte, []byte, error) {
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode JWT public key: %w", err)
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	})
+	publicPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: publicDER,
+	})
+	return privatePEM, publicPEM, nil
+}
+
+// PublicKeys returns the public keys of a JWT keys Secret by file name, without the private keys
+func PublicKeys(secret *corev1.Secret) map[string][]byte {
+	keys := map[string][]byte{}
+	for key, value := range secret.Data {
+		if strings.HasSuffix(key, publicKeySuffix) {
+			keys[key] = value
+		}
+	}
+	return keys
+}
+
+// TrustedKeyIDs returns the sorted IDs, joined by commas, of the public keys of the copy of the JWT keys Secret
+// referenced by a component, namespace being the default namespace of the reference.
+// It returns an empty string when the component references no Secret or the Secret is not copied yet.
+func TrustedKeyIDs(ctx context.Context, kclient kubernetes.Interface, namespace string, ref *corev1.SecretReference) (string, error) {
+	if ref == nil {
+		return "", nil
+	}
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+
+	secret, err := kclient.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get JWT public keys secret: %w", err)
+	}
+
+	var ids []string
+	for key := range PublicKeys(secret) {
+		ids = append(ids, strings.TrimSuffix(key, publicKeySuffix))
+	}
+	slices.Sort(ids)
+	return strings.Join(ids, ","), nil
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+func TestVerifyTokenDuringRotation(t *testing.T) {
+	jm, privateKeys := newTestJWTManager(t, "k1", "k2")
+	previous, _, err := jm.GenerateToken("pageserver/neon/ps", ScopeGenerationsAPI, "", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	// Switching: the new key signs, the previous one still verifies the tokens issued before the switch
+	jm.privateKey, jm.activeKeyID = privateKeys["k2"], "k2"
+	current, _, err := jm.GenerateToken("pageserver/neon/ps", ScopeGenerationsAPI, "", time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	if got := tokenKeyID(current); got != "k2" {
+		t.Errorf("kid after the switch = %q, want %q", got, "k2")
+	}
+	for name, token := range map[string]string{"previous": previous, "current": current} {
+		if _, err := jm.VerifyToken(token); err != nil {
+			t.Errorf("%s token rejected while both keys are published: %v", name, err)
+		}
+	}
+
+	// Retired: the previous key is removed once every token was reissued
+	delete(jm.publicKeys, "k1")
+	if _, err := jm.VerifyToken(previous); err == nil {
+		t.Errorf("token of the retired key accepted")
+	}
+	if _, err := jm.VerifyToken(current); err != nil {
+		t.Errorf("current token rejected after the retirement: %v", err)
+	}
+}
+
+func TestTokensReissued(t *testing.T) {
+	jm, _ := newTestJWTManager(t, "k2", "k1")
+	enableTestJWT(t, jm)
+
+	switchedAt := time.Now().Add(-time.Hour)
+	// issuedAgo returns the expiry annotation of a token issued some time ago
+	issuedAgo := func(d time.Duration) string {
+		return time.Now().Add(-d).Add(tokenTTL).UTC().Format(time.RFC3339)
+	}
+	tokenSecret := func(name, keyID, expiresAt string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      TokenSecretName(name),
+				Namespace: "neon",
+				Labels:    map[string]string{tokenScopeLabel: string(ScopeGenerationsAPI)},
+				Annotations: map[string]string{
+					tokenKeyIDAnnotation:        keyID,
+					TokenExpiresAtAnnotationKey: expiresAt,
+				},
+			},
+		}
+	}
+	statefulSet := func(name, expiresAt string, rolledOut bool) *appsv1.StatefulSet {
+		ss := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  "neon",
+				Labels:     map[string]string{operator.ManagedByLabelKey: operator.ManagedByLabelValue},
+				Generation: 2,
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{TokenExpiresAtAnnotationKey: expiresAt},
+					},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{
+				ObservedGeneration: 2,
+				CurrentRevision:    "ps-2",
+				UpdateRevision:     "ps-2",
+			},
+		}
+		if !rolledOut {
+			ss.Status.CurrentRevision = "ps-1"
+		}
+		return ss
+	}
+	settled := issuedAgo(2 * tokenProjectionDelay)
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    bool
+	}{
+		{
+			name: "token of the previous key",
+			objects: []runtime.Object{
+				tokenSecret("sk", "k1", settled),
+			},
+			want: false,
+		},
+		{
+			name: "token not projected yet",
+			objects: []runtime.Object{
+				tokenSecret("sk", "k2", issuedAgo(tokenProjectionDelay/2)),
+			},
+			want: false,
+		},
+		{
+			name: "token read from the projected file",
+			objects: []runtime.Object{
+				tokenSecret("sk", "k2", settled),
+			},
+			want: true,
+		},
+		{
+			name: "pods started with the previous token",
+			objects: []runtime.Object{
+				tokenSecret("ps", "k2", settled),
+				statefulSet("ps", issuedAgo(time.Hour+tokenTTL/2), true),
+			},
+			want: false,
+		},
+		{
+			name: "pods rolling out the token",
+			objects: []runtime.Object{
+				tokenSecret("ps", "k2", settled),
+				statefulSet("ps", settled, false),
+			},
+			want: false,
+		},
+		{
+			name: "pods rolled out with the token",
+			objects: []runtime.Object{
+				tokenSecret("ps", "k2", settled),
+				statefulSet("ps", settled, true),
+			},
+			want: true,
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &TokenRefresher{
+				nclient: fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
+				kclient: fake.NewClientset(tt.objects...),
+				logger:  slog.New(slog.DiscardHandler),
+			}
+
+			got, err := r.tokensReissued(context.Background(), "k2", switchedAt)
+			if err != nil {
+				t.Fatalf("tokensReissued() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("tokensReissued() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The operator does not sign with the key yet
+	r := &TokenRefresher{
+		nclient: fakeclient.NewClientBuilder().WithScheme(scheme).Build(),
+		kclient: fake.NewClientset(),
+		logger:  slog.New(slog.DiscardHandler),
+	}
+	if got, err := r.tokensReissued(context.Background(), "k1", switchedAt); err != nil || got {
+		t.Errorf("tokensReissued() of a key not signing = %v, %v, want false", got, err)
+	}
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	// DefaultTokenTTL is the lifetime of the tokens issued by the control plane.
+	// Safekeepers read their token file again when the kubelet projects a refreshed token, pageserver pods are
+	// rolled since the token is rendered into their config on startup.
+	DefaultTokenTTL = 7 * 24 * time.Hour
+
+	// TokenSecretKey is the key of the token in the token Secret of a component
+	TokenSecretKey = "token"
+	// TokenExpiresAtAnnotationKey is the expiry of the token of a component, set on its token Secret
+	TokenExpiresAtAnnotationKey = "neon.io/token-expires-at"
+
+	// tokenScopeLabel is the scope of the token of a token Secret, the refresh loop selects them by it
+	tokenScopeLabel = "neon.io/token-scope"
+	// tokenSubjectAnnotation is the subject of the token of a token Secret
+	tokenSubjectAnnotation = "neon.io/token-subject"
+	// tokenKeyIDAnnotation is the ID of the key the token of a token Secret is signed with
+	tokenKeyIDAnnotation = "neon.io/token-kid"
+
+	// tokenRefreshInterval is how often the refresh loop looks for tokens to refresh
+	tokenRefreshInterval = time.Minute
+)
+
+// ErrJWTDisabled is returned when a token is requested while JWT authentication is disabled
+var ErrJWTDisabled = errors.New("JWT authentication is disabled")
+
+var (
+	tokenTTL = DefaultTokenTTL
+	issuer   *JWTManager
+
+	// operatorTokens caches the tokens of the operator requests by scope
+	operatorTokens   = map[Scope]issuedToken{}
+	operatorTokensMu sync.Mutex
+)
+
+// issuedToken is a token, its expiry and the ID of the key it is signed with
+type issuedToken struct {
+	token     string
+	expiresAt time.Time
+	keyID     string
+}
+
+// activeKeyID returns the ID of the key tokens are signed with, empty when JWT is disabled
+func activeKeyID() string {
+	if !GetEnableJWT() || issuer == nil {
+		return ""
+	}
+	return issuer.ActiveKeyID()
+}
+
+// refreshDue reports whether a token expiring at expiresAt is in the last third of its lifetime
+func refreshDue(expiresAt time.Time) bool {
+	return time.Until(expiresAt) < tokenTTL/3
+}
+
+// IssueToken issues a token of the scope for a subject, expiring after the token TTL.
+// tenantID is only set on tenant scoped tokens.
+func IssueToken(subject string, scope Scope, tenantID string) (string, time.Time, error) {
+	if !GetEnableJWT() || issuer == nil {
+		return "", time.Time{}, ErrJWTDisabled
+	}
+	return issuer.GenerateToken(subject, scope, tenantID, tokenTTL)
+}
+
+// issueComputeToken issues a short lived token of the compute_ctl API of a compute, used to push specs to it
+func issueComputeToken(computeID string) (string, error) {
+	if !GetEnableJWT() || issuer == nil {
+		return "", ErrJWTDisabled
+	}
+	return issuer.GenerateComputeToken(controllerName, computeID, computeTokenTTL)
+}
+
+// GetToken returns a token of the scope for the requests of the operator, reissued before it expires
+// and when the signing key changes. It returns an empty token when JWT is disabled.
+func GetToken(scope Scope) string {
+	operatorTokensMu.Lock()
+	defer operatorTokensMu.Unlock()
+
+	if cached, ok := operatorTokens[scope]; ok && !refreshDue(cached.expiresAt) && cached.keyID == activeKeyID() {
+		return cached.token
+	}
+
+	token, expiresAt, err := IssueToken(controllerName, scope, "")
+	if err != nil {
+		return ""
+	}
+	operatorTokens[scope] = issuedToken{token: token, expiresAt: expiresAt, keyID: tokenKeyID(token)}
+	return token
+}
+
+// TokenSecretName returns the name of the Secret holding the token of a component.
+func TokenSecretName(name string) string {
+	return name + "-token"
+}
+
+// UpdateTokenSecret creates the Secret holding the token of a component, owned by the component and mounted
+// as a file by its pods so that the token never appears in a ConfigMap or a pod spec. The token is reissued
+// when its subject or scope changed, when it is due for a refresh or when the signing key changed,
+// the refresh loop keeps it fresh otherwise. It returns the expiry of the token, components reading the
+// token only on startup roll their pods when it changes. The Secret is deleted when JWT is disabled.
+func UpdateTokenSecret(ctx context.Context, kclient kubernetes.Interface, owner operator.Owner, subject string, scope Scope, labels map[string]string) (string, error) {
+	objMeta := owner.GetObjectMeta()
+	name := TokenSecretName(objMeta.GetName())
+	secrets := kclient.CoreV1().Secrets(objMeta.GetNamespace())
+
+	if !GetEnableJWT() {
+		if err := secrets.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to delete token secret %s: %w", name, err)
+		}
+		return "", nil
+	}
+
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	notFound := false
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			notFound = true
+		} else {
+			return "", fmt.Errorf("failed to get token secret %s: %w", name, err)
+		}
+	}
+
+	owned := false
+	if !notFound {
+		for _, ref := range secret.GetOwnerReferences() {
+			if ref.UID == objMeta.GetUID() {
+				owned = true
+				break
+			}
+		}
+
+		expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[TokenExpiresAtAnnotationKey])
+		if owned && err == nil && !refreshDue(expiresAt) &&
+			secret.Labels[tokenScopeLabel] == string(scope) &&
+			secret.Annotations[tokenSubjectAnnotation] == subject &&
+			secret.Annotations[tokenKeyIDAnnotation] == activeKeyID() &&
+			len(secret.Data[TokenSecretKey]) > 0 {
+			// No update needed
+			return secret.Annotations[TokenExpiresAtAnnotationKey], nil
+		}
+	}
+
+	token, expiresAt, err := IssueToken(subject, scope, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to issue token for %s: %w", subject, err)
+	}
+
+	if notFound {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: objMeta.GetNamespace(),
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+		operator.UpdateObject(secret, operator.WithOwner(owner))
+	} else if !owned {
+		operator.UpdateObject(secret, operator.WithOwner(owner))
+	}
+
+	setToken(secret, token, expiresAt, subject, scope)
+	operator.UpdateObject(secret, operator.WithLabels(labels))
+
+	if notFound {
+		if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create token secret %s: %w", name, err)
+		}
+	} else {
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to update token secret %s: %w", name, err)
+		}
+	}
+
+	return secret.Annotations[TokenExpiresAtAnnotationKey], nil
+}
+
+// setToken writes a token and what it was issued for into its Secret, replacing the previous token
+func setToken(secret *corev1.Secret, token string, expiresAt time.Time, subject string, scope Scope) {
+	secret.Data = map[string][]byte{
+		TokenSecretKey: []byte(token),
+	}
+	// The merge options keep existing values, the previous token is described by them
+	operator.UpdateObject(secret)
+	secret.Labels[tokenScopeLabel] = string(scope)
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[tokenSubjectAnnotation] = subject
+	secret.Annotations[tokenKeyIDAnnotation] = tokenKeyID(token)
+	secret.Annotations[TokenExpiresAtAnnotationKey] = expiresAt.UTC().Format(time.RFC3339)
+}
+
+// TokenRefresher reissues the tokens of the token Secrets before they expire, the safekeepers read the
+// new token from the projected file and the pageservers roll their pods. It pushes a fresh spec,
+// holding a new tenant token, to running computes.
+// It also drives the rotations of the signing key, see rotateKeys.
+type TokenRefresher struct {
+	nclient  client.Client
+	kclient  kubernetes.Interface
+	notifier *ComputeNotifier
+	logger   *slog.Logger
+}
+
+// NewTokenRefresher creates a new token refresher, run by the manager while it is the leader.
+func NewTokenRefresher(nclient client.Client, config *rest.Config, logger *slog.Logger) (*TokenRefresher, error) {
+	kclient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	logger = logger.With("component", "token-refresher")
+	return &TokenRefresher{
+		nclient:  nclient,
+		kclient:  kclient,
+		notifier: NewComputeNotifier(nclient, kclient, logger),
+		logger:   logger,
+	}, nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, a single replica refreshes the tokens.
+func (r *TokenRefresher) NeedLeaderElection() bool {
+	return true
+}
+
+// Start refreshes the tokens until the context is cancelled.
+func (r *TokenRefresher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(tokenRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if GetEnableJWT() {
+			configuredBefore, err := r.rotateKeys(ctx)
+			if err != nil {
+				r.logger.Error("failed to rotate JWT keys", "error", err)
+			}
+			if err := r.refreshSecrets(ctx); err != nil {
+				r.logger.Error("failed to refresh token secrets", "error", err)
+			}
+			if err := r.refreshComputes(ctx, configuredBefore); err != nil {
+				r.logger.Error("failed to refresh compute tokens", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshSecrets reissues the tokens of the token Secrets due for a refresh or signed with a previous key
+func (r *TokenRefresher) refreshSecrets(ctx context.Context) error {
+	secrets, err := r.kclient.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: tokenScopeLabel,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list token secrets: %w", err)
+	}
+
+	var errs []error
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[TokenExpiresAtAnnotationKey])
+		if err == nil && !refreshDue(expiresAt) && secret.Annotations[tokenKeyIDAnnotation] == activeKeyID() {
+			continue
+		}
+
+		subject := secret.Annotations[tokenSubjectAnnotation]
+		scope := Scope(secret.Labels[tokenScopeLabel])
+		token, expiresAt, err := IssueToken(subject, scope, "")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("secret %s/%s: %w", secret.Namespace, secret.Name, err))
+			continue
+		}
+
+		setToken(secret, token, expiresAt, subject, scope)
+		if _, err := r.kclient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("secret %s/%s: %w", secret.Namespace, secret.Name, err))
+			continue
+		}
+		r.logger.Info("Refreshed token", "namespace", secret.Namespace, "secret", secret.Name, "subject", subject, "expiresAt", expiresAt)
+	}
+
+	return errors.Join(errs...)
+}
+
+// refreshComputes pushes a spec with a new tenant token and the published keys to the running computes configured
+// in the last third of the lifetime of a token, or before configuredBefore during a key rotation.
+// Computes fetch a new spec when they start.
+func (r *TokenRefresher) refreshComputes(ctx context.Context, configuredBefore time.Time) error {
+	endpoints := &v1alpha1.ComputeEndpointList{}
+	if err := r.nclient.List(ctx, endpoints); err != nil {
+		return fmt.Errorf("failed to list compute endpoints: %w", err)
+	}
+
+	var errs []error
+	for i := range endpoints.Items {
+		ep := &endpoints.Items[i]
+		if !meta.IsStatusConditionTrue(ep.Status.Conditions, v1alpha1.ComputeEndpointConditionReady) {
+			continue
+		}
+		if configured := ep.Status.LastConfiguredTime; configured != nil &&
+			!refreshDue(configured.Add(tokenTTL)) && !configured.Time.Before(configuredBefore) {
+			continue
+		}
+
+		if err := r.notifier.Notify(ctx, ep); err != nil {
+			errs = append(errs, fmt.Errorf("compute endpoint %s/%s: %w", ep.Namespace, ep.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
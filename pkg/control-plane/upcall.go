@@ -0,0 +1,140 @@
+package controlplane
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// upcallPrefix is the path prefix of the pageserver upcall API.
+// Pageservers join their request paths onto control_plane_api, so it must end with a slash.
+const upcallPrefix = "/upcall/v1/"
+
+// ReAttachRequest is sent by a pageserver on startup to learn which tenant shards it should attach.
+type ReAttachRequest struct {
+	NodeID   uint64               `json:"node_id"`
+	Register *NodeRegisterRequest `json:"register,omitempty"`
+}
+
+// NodeRegisterRequest carries the listen addresses of a pageserver along with its re-attach request.
+type NodeRegisterRequest struct {
+	NodeID         uint64 `json:"node_id"`
+	ListenPgAddr   string `json:"listen_pg_addr"`
+	ListenPgPort   uint16 `json:"listen_pg_port"`
+	ListenHTTPAddr string `json:"listen_http_addr"`
+	ListenHTTPPort uint16 `json:"listen_http_port"`
+}
+
+// ReAttachResponseTenant describes a tenant shard the pageserver should attach.
+type ReAttachResponseTenant struct {
+	ID         string                 `json:"id"`
+	Generation *uint32                `json:"gen"`
+	Mode       string                 `json:"mode"`
+	StripeSize uint32                 `json:"stripe_size"`
+	Config     map[string]interface{} `json:"config"`
+}
+
+// ReAttachResponse is the response to a ReAttachRequest.
+type ReAttachResponse struct {
+	Tenants []ReAttachResponseTenant `json:"tenants"`
+}
+
+// ValidateRequestTenant is a tenant shard generation a pageserver wants to validate.
+type ValidateRequestTenant struct {
+	ID         string `json:"id"`
+	Generation uint32 `json:"gen"`
+}
+
+// ValidateRequest is sent by a pageserver before it deletes anything from remote storage.
+type ValidateRequest struct {
+	Tenants []ValidateRequestTenant `json:"tenants"`
+}
+
+// ValidateResponseTenant reports whether a tenant shard generation is still current.
+type ValidateResponseTenant struct {
+	ID    string `json:"id"`
+	Valid bool   `json:"valid"`
+}
+
+// ValidateResponse is the response to a ValidateRequest.
+type ValidateResponse struct {
+	Tenants []ValidateResponseTenant `json:"tenants"`
+}
+
+// UpcallPath returns the control_plane_api path pageservers must be configured with.
+func UpcallPath() string {
+	return upcallPrefix
+}
+
+// registerUpcallRoutes registers the pageserver upcall API handlers, authenticated with generations_api tokens
+func (cps *ControlPlaneServer) registerUpcallRoutes() {
+	cps.mux.HandleFunc("POST "+upcallPrefix+"re-attach", cps.authenticate(ScopeGenerationsAPI, cps.handleReAttach))
+	cps.mux.HandleFunc("POST "+upcallPrefix+"validate", cps.authenticate(ScopeGenerationsAPI, cps.handleValidate))
+}
+
+// handleReAttach serves POST /upcall/v1/re-attach.
+// A restarting pageserver gets back every tenant shard attached to it, each with a new generation.
+func (cps *ControlPlaneServer) handleReAttach(w http.ResponseWriter, r *http.Request) {
+	req := &ReAttachRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		cps.writeError(w, http.StatusBadRequest, "invalid re-attach request: "+err.Error())
+		return
+	}
+
+	if req.Register != nil {
+		pgAddr := net.JoinHostPort(req.Register.ListenPgAddr, strconv.Itoa(int(req.Register.ListenPgPort)))
+		httpAddr := net.JoinHostPort(req.Register.ListenHTTPAddr, strconv.Itoa(int(req.Register.ListenHTTPPort)))
+		if err := cps.nodes.Register(r.Context(), req.NodeID, pgAddr, httpAddr); err != nil {
+			// Unknown node IDs were not handed out by the registry, their generations cannot be trusted
+			cps.logger.Error("failed to register pageserver", "nodeID", req.NodeID, "error", err)
+			cps.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	tenants, err := cps.generations.ReAttach(r.Context(), req.NodeID)
+	if err != nil {
+		cps.logger.Error("failed to re-attach pageserver", "nodeID", req.NodeID, "error", err)
+		cps.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	cps.logger.Info("pageserver re-attach", "nodeID", req.NodeID, "tenants", len(tenants))
+
+	cps.writeJSON(w, http.StatusOK, ReAttachResponse{Tenants: tenants})
+}
+
+// handleValidate serves POST /upcall/v1/validate.
+// Pageservers call it before deleting anything from remote storage, and only proceed
+// for tenant shards whose generation is reported as valid.
+func (cps *ControlPlaneServer) handleValidate(w http.ResponseWriter, r *http.Request) {
+	req := &ValidateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		cps.writeError(w, http.StatusBadRequest, "invalid validate request: "+err.Error())
+		return
+	}
+
+	tenants, err := cps.generations.Validate(r.Context(), req.Tenants)
+	if err != nil {
+		cps.logger.Error("failed to validate generations", "error", err)
+		cps.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	cps.writeJSON(w, http.StatusOK, ValidateResponse{Tenants: tenants})
+}
+
+// writeJSON writes v as a JSON response body with the given status code
+func (cps *ControlPlaneServer) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		cps.logger.Error("failed to write response", "error", err)
+	}
+}
+
+// writeError writes a JSON error response in the format used by the neon storage controller
+func (cps *ControlPlaneServer) writeError(w http.ResponseWriter, status int, msg string) {
+	cps.writeJSON(w, status, map[string]string{"msg": msg})
+}
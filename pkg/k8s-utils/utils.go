@@ -13,7 +13,10 @@ const (
 	OPERATOR_NAMESPACE = "OPERATOR_NAMESPACE"
 	// InputHashAnnotationKey is the annotation key for storing input hash
 	InputHashAnnotationKey = "neon.io/input-hash"
-	NeonDefaultImage       = "ghcr.io/neondatabase/neon:latest"
+	// ConfigHashAnnotationKey is the pod template annotation key for storing the hash of the mounted config,
+	// changing it rolls the pods
+	ConfigHashAnnotationKey = "neon.io/config-hash"
+	NeonDefaultImage        = "ghcr.io/neondatabase/neon:latest"
 )
 
 // GetOperatorNamespace returns the namespace where the operator is running.
@@ -0,0 +1,262 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package neoncluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+const (
+	// certHashAnnotation is the hash of the CA certificate a copy or an issued certificate was made from
+	certHashAnnotation = "neon.io/cert-hash"
+
+	// componentCertValidity is the lifetime of the certificates issued for the components,
+	// they are reissued componentCertRenewBefore their expiry
+	componentCertValidity    = 365 * 24 * time.Hour
+	componentCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// certSecretName returns the name of the Secret holding the certificate issued for a component
+func certSecretName(name string) string {
+	return name + "-tls"
+}
+
+// copyControlPlaneCACert copies the CA certificate of the control plane into the namespace of the NeonCluster,
+// the components trust it to verify the control plane and each other. Only the certificate is copied, a serving
+// certificate provided without a CA is self-signed and copied as the CA. The copy of the serving certificate and
+// key made by previous versions is deleted.
+func (r *Operator) copyControlPlaneCACert(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) error {
+	if !controlplane.GetEnableTLS() {
+		// TLS not enabled, nothing to do
+		return nil
+	}
+
+	// Get the control plane namespace (operator namespace)
+	controlPlaneNamespace := k8sutils.GetOperatorNamespace()
+
+	if nc.Namespace != controlPlaneNamespace {
+		if err := r.deleteServingCertCopy(ctx, nc, logger); err != nil {
+			return err
+		}
+	}
+
+	// Get the control-plane-certs secret from control-plane namespace
+	sourceSecret, err := r.kclient.CoreV1().Secrets(controlPlaneNamespace).Get(ctx, controlPlaneDefaultSecretName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Warn("Control plane cert secret not found, skipping copy", "namespace", controlPlaneNamespace, "secret", controlPlaneDefaultSecretName)
+			return nil
+		}
+		return fmt.Errorf("failed to get control-plane cert secret: %w", err)
+	}
+
+	caCrtData, ok := sourceSecret.Data[controlplane.CACertKey]
+	if !ok {
+		caCrtData, ok = sourceSecret.Data[corev1.TLSCertKey]
+		if !ok {
+			return fmt.Errorf("neither %s nor %s key found in %s/%s secret", controlplane.CACertKey, corev1.TLSCertKey,
+				controlPlaneNamespace, controlPlaneDefaultSecretName)
+		}
+	}
+
+	hash := sha256.Sum256(caCrtData)
+	hashStr := hex.EncodeToString(hash[:])
+
+	// Try to get existing secret
+	existingSecret, err := r.kclient.CoreV1().Secrets(nc.Namespace).Get(ctx, controlplane.CACertSecretName, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get destination CA secret: %w", err)
+		}
+
+		// Create new secret with hash annotation
+		newSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controlplane.CACertSecretName,
+				Namespace: nc.Namespace,
+				Annotations: map[string]string{
+					certHashAnnotation: hashStr,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				controlplane.CACertKey: caCrtData,
+			},
+		}
+
+		_, err = r.kclient.CoreV1().Secrets(nc.Namespace).Create(ctx, newSecret, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create control-plane CA secret: %w", err)
+		}
+
+		logger.Info("Created control-plane CA secret in neon cluster namespace", "namespace", nc.Namespace, "secret", controlplane.CACertSecretName)
+		return nil
+	}
+
+	if existingSecret.Annotations[certHashAnnotation] == hashStr {
+		logger.Debug("Control-plane CA secret is up to date, no update needed", "namespace", nc.Namespace, "secret", controlplane.CACertSecretName)
+		return nil
+	}
+
+	// Replace the existing secret's data, it only ever holds the CA certificate
+	existingSecret = existingSecret.DeepCopy()
+	if existingSecret.Annotations == nil {
+		existingSecret.Annotations = make(map[string]string)
+	}
+	existingSecret.Data = map[string][]byte{
+		controlplane.CACertKey: caCrtData,
+	}
+	existingSecret.Annotations[certHashAnnotation] = hashStr
+
+	_, err = r.kclient.CoreV1().Secrets(nc.Namespace).Update(ctx, existingSecret, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update control-plane CA secret: %w", err)
+	}
+
+	logger.Info("Updated control-plane CA secret in neon cluster namespace", "namespace", nc.Namespace, "secret", controlplane.CACertSecretName)
+	return nil
+}
+
+// deleteServingCertCopy deletes the copy of the control plane serving certificate and key from the namespace
+// of the NeonCluster. Only a copy, annotated with the hash of its source, is deleted.
+func (r *Operator) deleteServingCertCopy(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) error {
+	secret, err := r.kclient.CoreV1().Secrets(nc.Namespace).Get(ctx, controlPlaneDefaultSecretName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get control-plane cert secret copy: %w", err)
+	}
+	if _, ok := secret.Annotations[certHashAnnotation]; !ok {
+		return nil
+	}
+
+	err = r.kclient.CoreV1().Secrets(nc.Namespace).Delete(ctx, controlPlaneDefaultSecretName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete control-plane cert secret copy: %w", err)
+	}
+
+	logger.Info("Deleted control-plane cert secret copy from neon cluster namespace", "namespace", nc.Namespace, "secret", controlPlaneDefaultSecretName)
+	return nil
+}
+
+// updateCertSecret issues a serving certificate for a component of the NeonCluster, signed by the CA of the
+// operator, and returns the reference of its Secret. The certificate covers the Service of the component and
+// its pods. It is reissued when the CA changed or before it expires. Without TLS or without a CA, generated
+// by the bootstrap or provided in the CA Secret, the component does not serve TLS and nil is returned.
+func (r *Operator) updateCertSecret(ctx context.Context, nc *v1alpha1.NeonCluster, name string, logger *slog.Logger) (*corev1.SecretReference, error) {
+	if !controlplane.GetEnableTLS() {
+		return nil, nil
+	}
+
+	controlPlaneNamespace := k8sutils.GetOperatorNamespace()
+	ca, err := r.kclient.CoreV1().Secrets(controlPlaneNamespace).Get(ctx, controlplane.CASecretName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("Control plane CA secret not found, the component does not serve TLS", "namespace", controlPlaneNamespace, "secret", controlplane.CASecretName, "component", name)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get control-plane CA secret: %w", err)
+	}
+
+	hash := sha256.Sum256(ca.Data[controlplane.CACertKey])
+	hashStr := hex.EncodeToString(hash[:])
+
+	secretName := certSecretName(name)
+	ref := &corev1.SecretReference{
+		Name:      secretName,
+		Namespace: nc.Namespace,
+	}
+
+	secret, err := r.kclient.CoreV1().Secrets(nc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+	notFound := apierrors.IsNotFound(err)
+	if err != nil && !notFound {
+		return nil, fmt.Errorf("failed to get %s certificate secret: %w", name, err)
+	}
+	if !notFound && secret.Annotations[certHashAnnotation] == hashStr && !certRenewalDue(secret.Data[corev1.TLSCertKey]) {
+		// No update needed
+		return ref, nil
+	}
+
+	domain := name + "." + nc.Namespace + ".svc"
+	dnsNames := append(controlplane.ServiceDNSNames(name, nc.Namespace), "*."+domain, "*."+domain+".cluster.local")
+	data, err := controlplane.IssueCertificate(ca.Data, name, dnsNames, componentCertValidity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue %s certificate: %w", name, err)
+	}
+
+	if notFound {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: nc.Namespace,
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+		operator.UpdateObject(secret, operator.WithOwner(nc))
+	} else {
+		secret = secret.DeepCopy()
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[certHashAnnotation] = hashStr
+	secret.Data = data
+
+	if notFound {
+		if _, err := r.kclient.CoreV1().Secrets(nc.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create %s certificate secret: %w", name, err)
+		}
+		logger.Info("Issued component certificate", "namespace", nc.Namespace, "secret", secretName)
+		return ref, nil
+	}
+
+	if _, err := r.kclient.CoreV1().Secrets(nc.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to update %s certificate secret: %w", name, err)
+	}
+	logger.Info("Reissued component certificate", "namespace", nc.Namespace, "secret", secretName)
+	return ref, nil
+}
+
+// certRenewalDue reports whether a certificate is unreadable or expires within componentCertRenewBefore
+func certRenewalDue(certPEM []byte) bool {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	return time.Until(cert.NotAfter) < componentCertRenewBefore
+}
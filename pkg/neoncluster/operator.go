@@ -22,14 +22,18 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
 	corev1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
@@ -39,8 +43,8 @@ import (
 )
 
 const (
-	controlPlaneDefaultSecretName = "control-plane-certs"
-	controlPlaneJWTSecretName     = "control-plane-jwt-keys"
+	controlPlaneDefaultSecretName = controlplane.CertsSecretName
+	controlPlaneJWTSecretName     = controlplane.JWTKeysSecretName
 )
 
 // Operator manages lifecycle for NeonCluster resources.
@@ -49,6 +53,8 @@ type Operator struct {
 	kclient kubernetes.Interface
 	scheme  *runtime.Scheme
 	logger  *slog.Logger
+	// generations are detached or dropped during the teardown, depending on the deletion policy
+	generations *controlplane.GenerationStore
 }
 
 // Profiles holds references to all profile resources for a NeonCluster
@@ -69,15 +75,32 @@ func New(client client.Client, scheme *runtime.Scheme, logger *slog.Logger, conf
 	}
 
 	return &Operator{
-		logger:  logger,
-		nclient: client,
-		kclient: kclient,
-		scheme:  scheme,
+		logger:      logger,
+		nclient:     client,
+		kclient:     kclient,
+		scheme:      scheme,
+		generations: controlplane.NewGenerationStore(kclient),
 	}, nil
 }
 
+// pageServerName returns the name of the PageServer of a NeonCluster
+func pageServerName(nc *v1alpha1.NeonCluster) string {
+	return nc.Name + "-pageserver"
+}
+
+// safeKeeperName returns the name of the SafeKeeper of a NeonCluster
+func safeKeeperName(nc *v1alpha1.NeonCluster) string {
+	return nc.Name + "-safekeeper"
+}
+
+// storageBrokerName returns the name of the StorageBroker of a NeonCluster
+func storageBrokerName(nc *v1alpha1.NeonCluster) string {
+	return nc.Name + "-broker"
+}
+
 // sync runes everytime where there is reconcile event for neocluster.
-func (r *Operator) sync(ctx context.Context, name, namespace string) error {
+// It reports whether a component or the teardown is not settled yet, in which case the status is refreshed later.
+func (r *Operator) sync(ctx context.Context, name, namespace string) (bool, error) {
 	nc := &corev1alpha1.NeonCluster{}
 	if err := r.nclient.Get(ctx, client.ObjectKey{
 		Name:      name,
@@ -86,9 +109,9 @@ func (r *Operator) sync(ctx context.Context, name, namespace string) error {
 		if apierrors.IsNotFound(err) {
 			// NeonCluster resource not found, could have been deleted after reconcile request.
 			// Return and don't requeue
-			return nil
+			return false, nil
 		}
-		return err
+		return false, err
 	}
 
 	nc = nc.DeepCopy()
@@ -98,33 +121,99 @@ func (r *Operator) sync(ctx context.Context, name, namespace string) error {
 
 	logger.Info("Sync neoncluster")
 
-	pf, err := r.getProfiles(ctx, nc)
-	if err != nil {
-		return err
+	if !nc.DeletionTimestamp.IsZero() {
+		return r.teardown(ctx, nc, logger)
 	}
 
-	if err := r.updatePageServer(ctx, nc, pf.pageServer, logger); err != nil {
-		return err
+	if !controllerutil.ContainsFinalizer(nc, neonClusterFinalizer) {
+		// Persist the finalizer before any component is created, the update triggers a new reconcile
+		controllerutil.AddFinalizer(nc, neonClusterFinalizer)
+		if err := r.nclient.Update(ctx, nc); err != nil {
+			return false, fmt.Errorf("failed to add neoncluster finalizer: %w", err)
+		}
+		return false, nil
 	}
 
-	if err := r.updateSafeKeeper(ctx, nc, pf.safeKeeper, logger); err != nil {
-		return err
+	stage, syncErr := r.reconcileComponents(ctx, nc, logger)
+
+	// Status is written even when the reconcile failed, Degraded reports the failure
+	progressing, err := r.updateStatus(ctx, nc, stage, syncErr)
+	if syncErr != nil {
+		if err != nil {
+			logger.Error("failed to update neoncluster status", "error", err)
+		}
+		return false, syncErr
 	}
 
-	if err := r.copyControlPlaneCertSecret(ctx, nc, logger); err != nil {
-		return err
+	return progressing || stage != nil, err
+}
+
+// reconcileComponents creates or updates the children of the NeonCluster in rollout order:
+// the storage broker, the safekeepers and the pageservers. Each stage is applied once the previous
+// one is ready, the returned stage is the one the rollout waits for, or nil once every stage is applied.
+func (r *Operator) reconcileComponents(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (*rolloutStage, error) {
+	shared, err := r.getProfiles(ctx, nc)
+	if err != nil {
+		return nil, err
+	}
+
+	pf, err := applyOverrides(nc, shared)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every component trusts the copied CA certificate
+	if err := r.copyControlPlaneCACert(ctx, nc, logger); err != nil {
+		return nil, err
 	}
 
 	if err := r.copyControlPlanePublicKey(ctx, nc, logger); err != nil {
-		return err
+		return nil, err
+	}
+
+	if nc.Spec.StorageBroker != nil {
+		if err := r.ensureProfile(ctx, nc, shared.storageBroker, &v1alpha1.StorageBrokerProfile{}, pf.storageBroker, storageBrokerProfileSpec, logger); err != nil {
+			return nil, err
+		}
+	}
+	sb, err := r.updateStorageBroker(ctx, nc, pf.storageBroker, logger)
+	if err != nil {
+		return nil, err
+	}
+	if stage := storageBrokerStage(sb); stage != nil {
+		logger.Info("Waiting for storagebroker before rolling out safekeepers", "message", stage.message)
+		return stage, nil
+	}
+
+	if nc.Spec.SafeKeeper != nil {
+		if err := r.ensureProfile(ctx, nc, shared.safeKeeper, &v1alpha1.SafeKeeperProfile{}, pf.safeKeeper, safeKeeperProfileSpec, logger); err != nil {
+			return nil, err
+		}
+	}
+	sk, err := r.updateSafeKeeper(ctx, nc, pf.safeKeeper, logger)
+	if err != nil {
+		return nil, err
+	}
+	if stage := safeKeeperStage(sk); stage != nil {
+		logger.Info("Waiting for safekeeper quorum before rolling out pageservers", "message", stage.message)
+		return stage, nil
 	}
 
-	return r.updateStorageBroker(ctx, nc, pf.storageBroker, logger)
+	if nc.Spec.PageServer != nil {
+		if err := r.ensureProfile(ctx, nc, shared.pageServer, &v1alpha1.PageServerProfile{}, pf.pageServer, pageServerProfileSpec, logger); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r.updatePageServer(ctx, nc, pf.pageServer, logger); err != nil {
+		return nil, err
+	}
 
+	return nil, r.deleteStaleProfiles(ctx, nc)
 }
 
-func (r *Operator) updatePageServer(ctx context.Context, nc *v1alpha1.NeonCluster, profile *v1alpha1.PageServerProfile, logger *slog.Logger) error {
-	psName := nc.Name + "-pageserver"
+// updatePageServer creates or updates the PageServer of the NeonCluster and returns it
+func (r *Operator) updatePageServer(ctx context.Context, nc *v1alpha1.NeonCluster, profile *v1alpha1.PageServerProfile, logger *slog.Logger) (*v1alpha1.PageServer, error) {
+	psName := pageServerName(nc)
 
 	ps := &v1alpha1.PageServer{}
 	err := r.nclient.Get(ctx, client.ObjectKey{
@@ -135,7 +224,7 @@ func (r *Operator) updatePageServer(ctx context.Context, nc *v1alpha1.NeonCluste
 	notFound := apierrors.IsNotFound(err)
 
 	if err != nil && !notFound {
-		return fmt.Errorf("failed to get pageserver: %w", err)
+		return nil, fmt.Errorf("failed to get pageserver: %w", err)
 	}
 
 	if !notFound {
@@ -151,12 +240,10 @@ func (r *Operator) updatePageServer(ctx context.Context, nc *v1alpha1.NeonCluste
 		ObjectStorage: nc.Spec.ObjectStorage,
 	}
 
-	// Add TLS secret reference if TLS is enabled
-	if controlplane.GetEnableTLS() {
-		desiredSpec.TLSSecretRef = &corev1.SecretReference{
-			Name:      controlPlaneDefaultSecretName,
-			Namespace: nc.Namespace,
-		}
+	// Add the TLS secret reference of a certificate issued for the component if TLS is enabled
+	desiredSpec.TLSSecretRef, err = r.updateCertSecret(ctx, nc, psName, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	// Add JWT public key secret reference if JWT is enabled
@@ -170,14 +257,15 @@ func (r *Operator) updatePageServer(ctx context.Context, nc *v1alpha1.NeonCluste
 	// Calculate hash of desired spec
 	hash, err := k8sutils.CreateInputHash(metav1.ObjectMeta{}, desiredSpec)
 	if err != nil {
-		return fmt.Errorf("failed to create input hash for pageserver: %w", err)
+		return nil, fmt.Errorf("failed to create input hash for pageserver: %w", err)
 	}
 
-	// Check if update is needed
+	// Check if update is needed, manual edits of the spec are reverted even when the hash is unchanged
 	if !notFound {
-		if ps.Annotations != nil && ps.Annotations[k8sutils.InputHashAnnotationKey] == hash {
+		if ps.Annotations != nil && ps.Annotations[k8sutils.InputHashAnnotationKey] == hash &&
+			equality.Semantic.DeepDerivative(desiredSpec, ps.Spec) {
 			// No update needed
-			return nil
+			return ps, nil
 		}
 	}
 
@@ -203,12 +291,12 @@ func (r *Operator) updatePageServer(ctx context.Context, nc *v1alpha1.NeonCluste
 
 		err = r.nclient.Create(ctx, ps)
 		if err != nil {
-			return fmt.Errorf("failed to create pageserver: %w", err)
+			return nil, fmt.Errorf("failed to create pageserver: %w", err)
 		}
 
 		logger.Info("Created pageserver", "name", ps.Name, "namespace", ps.Namespace)
 
-		return nil
+		return ps, nil
 	}
 
 	ps.Spec = desiredSpec
@@ -219,16 +307,17 @@ func (r *Operator) updatePageServer(ctx context.Context, nc *v1alpha1.NeonCluste
 
 	err = r.nclient.Update(ctx, ps)
 	if err != nil {
-		return fmt.Errorf("failed to update pageserver: %w", err)
+		return nil, fmt.Errorf("failed to update pageserver: %w", err)
 	}
 
 	logger.Info("Updated pageserver", "name", ps.Name, "namespace", ps.Namespace)
 
-	return nil
+	return ps, nil
 }
 
-func (r *Operator) updateSafeKeeper(ctx context.Context, nc *v1alpha1.NeonCluster, profile *v1alpha1.SafeKeeperProfile, logger *slog.Logger) error {
-	skname := nc.Name + "-safekeeper"
+// updateSafeKeeper creates or updates the SafeKeeper of the NeonCluster and returns it
+func (r *Operator) updateSafeKeeper(ctx context.Context, nc *v1alpha1.NeonCluster, profile *v1alpha1.SafeKeeperProfile, logger *slog.Logger) (*v1alpha1.SafeKeeper, error) {
+	skname := safeKeeperName(nc)
 
 	sk := &v1alpha1.SafeKeeper{}
 	err := r.nclient.Get(ctx, client.ObjectKey{
@@ -239,7 +328,7 @@ func (r *Operator) updateSafeKeeper(ctx context.Context, nc *v1alpha1.NeonCluste
 	notFound := apierrors.IsNotFound(err)
 
 	if err != nil && !notFound {
-		return fmt.Errorf("failed to get safekeeper: %w", err)
+		return nil, fmt.Errorf("failed to get safekeeper: %w", err)
 	}
 
 	if !notFound {
@@ -252,14 +341,13 @@ func (r *Operator) updateSafeKeeper(ctx context.Context, nc *v1alpha1.NeonCluste
 			Name:      profile.Name,
 			Namespace: profile.Namespace,
 		},
+		ObjectStorage: nc.Spec.ObjectStorage,
 	}
 
-	// Add TLS secret reference if TLS is enabled
-	if controlplane.GetEnableTLS() {
-		desiredSpec.TLSSecretRef = &corev1.SecretReference{
-			Name:      controlPlaneDefaultSecretName,
-			Namespace: nc.Namespace,
-		}
+	// Add the TLS secret reference of a certificate issued for the component if TLS is enabled
+	desiredSpec.TLSSecretRef, err = r.updateCertSecret(ctx, nc, skname, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	// Add JWT public key secret reference if JWT is enabled
@@ -273,14 +361,15 @@ func (r *Operator) updateSafeKeeper(ctx context.Context, nc *v1alpha1.NeonCluste
 	// Calculate hash of desired spec
 	hash, err := k8sutils.CreateInputHash(metav1.ObjectMeta{}, desiredSpec)
 	if err != nil {
-		return fmt.Errorf("failed to create input hash for safekeeper: %w", err)
+		return nil, fmt.Errorf("failed to create input hash for safekeeper: %w", err)
 	}
 
-	// Check if update is needed
+	// Check if update is needed, manual edits of the spec are reverted even when the hash is unchanged
 	if !notFound {
-		if sk.Annotations != nil && sk.Annotations[k8sutils.InputHashAnnotationKey] == hash {
+		if sk.Annotations != nil && sk.Annotations[k8sutils.InputHashAnnotationKey] == hash &&
+			equality.Semantic.DeepDerivative(desiredSpec, sk.Spec) {
 			// No update needed
-			return nil
+			return sk, nil
 		}
 	}
 
@@ -306,12 +395,12 @@ func (r *Operator) updateSafeKeeper(ctx context.Context, nc *v1alpha1.NeonCluste
 
 		err = r.nclient.Create(ctx, sk)
 		if err != nil {
-			return fmt.Errorf("failed to create safekeeper: %w", err)
+			return nil, fmt.Errorf("failed to create safekeeper: %w", err)
 		}
 
 		logger.Info("Created safekeeper", "name", sk.Name, "namespace", sk.Namespace)
 
-		return nil
+		return sk, nil
 	}
 
 	sk.Spec = desiredSpec
@@ -322,16 +411,17 @@ func (r *Operator) updateSafeKeeper(ctx context.Context, nc *v1alpha1.NeonCluste
 
 	err = r.nclient.Update(ctx, sk)
 	if err != nil {
-		return fmt.Errorf("failed to update safekeeper: %w", err)
+		return nil, fmt.Errorf("failed to update safekeeper: %w", err)
 	}
 
 	logger.Info("Updated safekeeper", "name", sk.Name, "namespace", sk.Namespace)
 
-	return nil
+	return sk, nil
 }
 
-func (r *Operator) updateStorageBroker(ctx context.Context, nc *v1alpha1.NeonCluster, profile *v1alpha1.StorageBrokerProfile, logger *slog.Logger) error {
-	sbname := nc.Name + "-broker"
+// updateStorageBroker creates or updates the StorageBroker of the NeonCluster and returns it
+func (r *Operator) updateStorageBroker(ctx context.Context, nc *v1alpha1.NeonCluster, profile *v1alpha1.StorageBrokerProfile, logger *slog.Logger) (*v1alpha1.StorageBroker, error) {
+	sbname := storageBrokerName(nc)
 
 	sb := &v1alpha1.StorageBroker{}
 	err := r.nclient.Get(ctx, client.ObjectKey{
@@ -342,7 +432,7 @@ func (r *Operator) updateStorageBroker(ctx context.Context, nc *v1alpha1.NeonClu
 	notFound := apierrors.IsNotFound(err)
 
 	if err != nil && !notFound {
-		return fmt.Errorf("failed to get storagebroker: %w", err)
+		return nil, fmt.Errorf("failed to get storagebroker: %w", err)
 	}
 
 	if !notFound {
@@ -357,25 +447,24 @@ func (r *Operator) updateStorageBroker(ctx context.Context, nc *v1alpha1.NeonClu
 		},
 	}
 
-	// Add TLS secret reference if TLS is enabled
-	if controlplane.GetEnableTLS() {
-		desiredSpec.TLSSecretRef = &corev1.SecretReference{
-			Name:      controlPlaneDefaultSecretName,
-			Namespace: nc.Namespace,
-		}
+	// Add the TLS secret reference of a certificate issued for the component if TLS is enabled
+	desiredSpec.TLSSecretRef, err = r.updateCertSecret(ctx, nc, sbname, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate hash of desired spec
 	hash, err := k8sutils.CreateInputHash(metav1.ObjectMeta{}, desiredSpec)
 	if err != nil {
-		return fmt.Errorf("failed to create input hash for storagebroker: %w", err)
+		return nil, fmt.Errorf("failed to create input hash for storagebroker: %w", err)
 	}
 
-	// Check if update is needed
+	// Check if update is needed, manual edits of the spec are reverted even when the hash is unchanged
 	if !notFound {
-		if sb.Annotations != nil && sb.Annotations[k8sutils.InputHashAnnotationKey] == hash {
+		if sb.Annotations != nil && sb.Annotations[k8sutils.InputHashAnnotationKey] == hash &&
+			equality.Semantic.DeepDerivative(desiredSpec, sb.Spec) {
 			// No update needed
-			return nil
+			return sb, nil
 		}
 	}
 
@@ -401,12 +490,12 @@ func (r *Operator) updateStorageBroker(ctx context.Context, nc *v1alpha1.NeonClu
 
 		err = r.nclient.Create(ctx, sb)
 		if err != nil {
-			return fmt.Errorf("failed to create storagebroker: %w", err)
+			return nil, fmt.Errorf("failed to create storagebroker: %w", err)
 		}
 
 		logger.Info("Created storagebroker", "name", sb.Name, "namespace", sb.Namespace)
 
-		return nil
+		return sb, nil
 	}
 
 	sb.Spec = desiredSpec
@@ -417,118 +506,12 @@ func (r *Operator) updateStorageBroker(ctx context.Context, nc *v1alpha1.NeonClu
 
 	err = r.nclient.Update(ctx, sb)
 	if err != nil {
-		return fmt.Errorf("failed to update storagebroker: %w", err)
+		return nil, fmt.Errorf("failed to update storagebroker: %w", err)
 	}
 
 	logger.Info("Updated storagebroker", "name", sb.Name, "namespace", sb.Namespace)
 
-	return nil
-}
-
-func (r *Operator) copyControlPlaneCertSecret(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) error {
-	if !controlplane.GetEnableTLS() {
-		// TLS not enabled, nothing to do
-		return nil
-	}
-	const (
-		tlsCertKey     = "tls.crt"
-		tlsKeyKey      = "tls.key"
-		hashAnnotation = "neon.io/cert-hash"
-	)
-
-	secretName := controlPlaneDefaultSecretName
-
-	// Get the control plane namespace (operator namespace)
-	controlPlaneNamespace := k8sutils.GetOperatorNamespace()
-
-	// Get the control-plane-certs secret from control-plane namespace
-	sourceSecret, err := r.kclient.CoreV1().Secrets(controlPlaneNamespace).Get(ctx, secretName, metav1.GetOptions{})
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			logger.Warn("Control plane cert secret not found, skipping copy", "namespace", controlPlaneNamespace, "secret", secretName)
-			return nil
-		}
-		return fmt.Errorf("failed to get control-plane cert secret: %w", err)
-	}
-
-	// Extract tls.crt and tls.key data
-	tlsCrtData, ok := sourceSecret.Data[tlsCertKey]
-	if !ok {
-		return fmt.Errorf("tls.crt key not found in %s/%s secret", controlPlaneNamespace, secretName)
-	}
-
-	tlsKeyData, ok := sourceSecret.Data[tlsKeyKey]
-	if !ok {
-		return fmt.Errorf("tls.key key not found in %s/%s secret", controlPlaneNamespace, secretName)
-	}
-
-	// Calculate hash of combined tls.crt and tls.key content
-	hash := sha256.Sum256(append(tlsCrtData, tlsKeyData...))
-	hashStr := hex.EncodeToString(hash[:])
-
-	// Try to get existing secret
-	existingSecret, err := r.kclient.CoreV1().Secrets(nc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
-	if err != nil {
-		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to get destination secret: %w", err)
-		}
-
-		// Create new secret with hash annotation
-		newSecret := &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      secretName,
-				Namespace: nc.Namespace,
-				Annotations: map[string]string{
-					hashAnnotation: hashStr,
-				},
-			},
-			Type: corev1.SecretTypeOpaque,
-			Data: map[string][]byte{
-				tlsCertKey: tlsCrtData,
-				tlsKeyKey:  tlsKeyData,
-			},
-		}
-
-		_, err = r.kclient.CoreV1().Secrets(nc.Namespace).Create(ctx, newSecret, metav1.CreateOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to create control-plane cert secret: %w", err)
-		}
-
-		logger.Info("Created control-plane cert secret in neon cluster namespace", "namespace", nc.Namespace, "secret", secretName)
-		return nil
-	}
-
-	// Check if hash has changed
-	existingHash := ""
-	if existingSecret.Annotations != nil {
-		existingHash = existingSecret.Annotations[hashAnnotation]
-	}
-
-	if existingHash == hashStr {
-		logger.Debug("Control-plane cert secret is up to date, no update needed", "namespace", nc.Namespace, "secret", secretName)
-		return nil
-	}
-
-	// Update existing secret's tls.crt, tls.key and hash annotation
-	existingSecret = existingSecret.DeepCopy()
-	if existingSecret.Data == nil {
-		existingSecret.Data = make(map[string][]byte)
-	}
-	if existingSecret.Annotations == nil {
-		existingSecret.Annotations = make(map[string]string)
-	}
-
-	existingSecret.Data[tlsCertKey] = tlsCrtData
-	existingSecret.Data[tlsKeyKey] = tlsKeyData
-	existingSecret.Annotations[hashAnnotation] = hashStr
-
-	_, err = r.kclient.CoreV1().Secrets(nc.Namespace).Update(ctx, existingSecret, metav1.UpdateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to update control-plane cert secret: %w", err)
-	}
-
-	logger.Info("Updated control-plane cert secret in neon cluster namespace", "namespace", nc.Namespace, "secret", secretName)
-	return nil
+	return sb, nil
 }
 
 func (r *Operator) copyControlPlanePublicKey(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) error {
@@ -536,10 +519,7 @@ func (r *Operator) copyControlPlanePublicKey(ctx context.Context, nc *v1alpha1.N
 		// JWT not enabled, nothing to do
 		return nil
 	}
-	const (
-		jwtPublicKeyKey = "jwt.pub"
-		hashAnnotation  = "neon.io/jwt-public-key-hash"
-	)
+	const hashAnnotation = "neon.io/jwt-public-key-hash"
 
 	// Get the control plane namespace (operator namespace)
 	controlPlaneNamespace := k8sutils.GetOperatorNamespace()
@@ -554,15 +534,20 @@ func (r *Operator) copyControlPlanePublicKey(ctx context.Context, nc *v1alpha1.N
 		return fmt.Errorf("failed to get control-plane JWT secret: %w", err)
 	}
 
-	// Extract jwt.pub data
-	jwtPubData, ok := sourceSecret.Data[jwtPublicKeyKey]
-	if !ok {
-		return fmt.Errorf("jwt.pub key not found in %s/%s secret", controlPlaneNamespace, controlPlaneJWTSecretName)
+	// Extract the public keys, every published key is trusted by the components
+	publicKeys := controlplane.PublicKeys(sourceSecret)
+	if len(publicKeys) == 0 {
+		return fmt.Errorf("no public key found in %s/%s secret", controlPlaneNamespace, controlPlaneJWTSecretName)
 	}
 
-	// Calculate hash of jwt.pub content
-	hash := sha256.Sum256(jwtPubData)
-	hashStr := hex.EncodeToString(hash[:])
+	// Calculate hash of the public keys, in file name order
+	names := slices.Sorted(maps.Keys(publicKeys))
+	hasher := sha256.New()
+	for _, name := range names {
+		hasher.Write([]byte(name))
+		hasher.Write(publicKeys[name])
+	}
+	hashStr := hex.EncodeToString(hasher.Sum(nil))
 
 	// Try to get existing secret
 	existingSecret, err := r.kclient.CoreV1().Secrets(nc.Namespace).Get(ctx, controlPlaneJWTSecretName, metav1.GetOptions{})
@@ -581,9 +566,7 @@ func (r *Operator) copyControlPlanePublicKey(ctx context.Context, nc *v1alpha1.N
 				},
 			},
 			Type: corev1.SecretTypeOpaque,
-			Data: map[string][]byte{
-				jwtPublicKeyKey: jwtPubData,
-			},
+			Data: publicKeys,
 		}
 
 		_, err = r.kclient.CoreV1().Secrets(nc.Namespace).Create(ctx, newSecret, metav1.CreateOptions{})
@@ -606,16 +589,13 @@ func (r *Operator) copyControlPlanePublicKey(ctx context.Context, nc *v1alpha1.N
 		return nil
 	}
 
-	// Update existing secret's jwt.pub and hash annotation
+	// Replace the existing secret's public keys, retired keys are removed
 	existingSecret = existingSecret.DeepCopy()
-	if existingSecret.Data == nil {
-		existingSecret.Data = make(map[string][]byte)
-	}
 	if existingSecret.Annotations == nil {
 		existingSecret.Annotations = make(map[string]string)
 	}
 
-	existingSecret.Data[jwtPublicKeyKey] = jwtPubData
+	existingSecret.Data = publicKeys
 	existingSecret.Annotations[hashAnnotation] = hashStr
 
 	_, err = r.kclient.CoreV1().Secrets(nc.Namespace).Update(ctx, existingSecret, metav1.UpdateOptions{})
@@ -0,0 +1,250 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package neoncluster
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+	"github.com/stateless-pg/stateless-pg/pkg/profile"
+)
+
+// applyOverrides returns the profiles the components of the NeonCluster run with.
+// A component with overrides runs with a profile generated for the NeonCluster, named after the component,
+// holding the referenced profile merged with the overrides. Other components keep the shared profile.
+// Generated profiles are only persisted by ensureProfile, when the rollout reaches their component.
+// They have no rollout policy, changes of the shared profile reach them through its rollout.
+func applyOverrides(nc *v1alpha1.NeonCluster, pf *Profiles) (*Profiles, error) {
+	result := &Profiles{
+		pageServer:    pf.pageServer,
+		safeKeeper:    pf.safeKeeper,
+		storageBroker: pf.storageBroker,
+	}
+
+	if overrides := nc.Spec.PageServer; overrides != nil {
+		psp := &v1alpha1.PageServerProfile{ObjectMeta: generatedProfileMeta(nc, pageServerName(nc))}
+		if err := mergeOverrides(&pf.pageServer.Spec, overrides, &psp.Spec); err != nil {
+			return nil, fmt.Errorf("invalid pageServer overrides: %w", err)
+		}
+		applyComponentSpec(overrides, &psp.Spec.CommonFields, &psp.Spec.MinReplicas, &psp.Spec.MaxReplicas)
+		psp.Spec.Rollout = nil
+		result.pageServer = psp
+	}
+
+	if overrides := nc.Spec.SafeKeeper; overrides != nil {
+		skp := &v1alpha1.SafeKeeperProfile{ObjectMeta: generatedProfileMeta(nc, safeKeeperName(nc))}
+		if err := mergeOverrides(&pf.safeKeeper.Spec, overrides, &skp.Spec); err != nil {
+			return nil, fmt.Errorf("invalid safeKeeper overrides: %w", err)
+		}
+		applyComponentSpec(overrides, &skp.Spec.CommonFields, &skp.Spec.MinReplicas, &skp.Spec.MaxReplicas)
+		skp.Spec.Rollout = nil
+		result.safeKeeper = skp
+	}
+
+	if overrides := nc.Spec.StorageBroker; overrides != nil {
+		sbp := &v1alpha1.StorageBrokerProfile{ObjectMeta: generatedProfileMeta(nc, storageBrokerName(nc))}
+		if err := mergeOverrides(&pf.storageBroker.Spec, overrides, &sbp.Spec); err != nil {
+			return nil, fmt.Errorf("invalid storageBroker overrides: %w", err)
+		}
+		applyComponentSpec(overrides, &sbp.Spec.CommonFields, &sbp.Spec.MinReplicas, &sbp.Spec.MaxReplicas)
+		sbp.Spec.Rollout = nil
+		result.storageBroker = sbp
+	}
+
+	return result, nil
+}
+
+// pageServerProfileSpec returns the spec of a PageServerProfile
+func pageServerProfileSpec(obj client.Object) interface{} {
+	return &obj.(*v1alpha1.PageServerProfile).Spec
+}
+
+// safeKeeperProfileSpec returns the spec of a SafeKeeperProfile
+func safeKeeperProfileSpec(obj client.Object) interface{} {
+	return &obj.(*v1alpha1.SafeKeeperProfile).Spec
+}
+
+// storageBrokerProfileSpec returns the spec of a StorageBrokerProfile
+func storageBrokerProfileSpec(obj client.Object) interface{} {
+	return &obj.(*v1alpha1.StorageBrokerProfile).Spec
+}
+
+// deleteStaleProfiles deletes the profiles generated for components whose overrides were removed,
+// once their components run with the shared profile again
+func (r *Operator) deleteStaleProfiles(ctx context.Context, nc *v1alpha1.NeonCluster) error {
+	if nc.Spec.PageServer == nil {
+		if err := r.deleteGeneratedProfile(ctx, nc, &v1alpha1.PageServerProfile{}, pageServerName(nc)); err != nil {
+			return err
+		}
+	}
+	if nc.Spec.SafeKeeper == nil {
+		if err := r.deleteGeneratedProfile(ctx, nc, &v1alpha1.SafeKeeperProfile{}, safeKeeperName(nc)); err != nil {
+			return err
+		}
+	}
+	if nc.Spec.StorageBroker == nil {
+		if err := r.deleteGeneratedProfile(ctx, nc, &v1alpha1.StorageBrokerProfile{}, storageBrokerName(nc)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeOverrides merges the profileOverride of a component on top of the spec of its profile into out
+func mergeOverrides(base interface{}, overrides *v1alpha1.NeonClusterComponentSpec, out interface{}) error {
+	var patch []byte
+	if overrides.ProfileOverride != nil {
+		patch = overrides.ProfileOverride.Raw
+	}
+	return profile.Merge(base, patch, out)
+}
+
+// applyComponentSpec applies the replicas, image and resources of a component on top of its merged profile.
+// maxReplicas is raised to the replicas so that the profile stays consistent.
+func applyComponentSpec(overrides *v1alpha1.NeonClusterComponentSpec, common *v1alpha1.CommonFields, minReplicas, maxReplicas **int64) {
+	if overrides.Replicas != nil {
+		replicas := int64(*overrides.Replicas)
+		*minReplicas = &replicas
+		if *maxReplicas == nil || **maxReplicas < replicas {
+			max := replicas
+			*maxReplicas = &max
+		}
+	}
+	if overrides.Image != nil {
+		image := *overrides.Image
+		common.Image = &image
+	}
+	if overrides.Resources != nil {
+		common.Resources = *overrides.Resources.DeepCopy()
+	}
+}
+
+// generatedProfileMeta returns the metadata of a profile generated for the NeonCluster
+func generatedProfileMeta(nc *v1alpha1.NeonCluster, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: nc.Namespace,
+	}
+}
+
+// ensureProfile creates or updates a profile generated for the NeonCluster from the resolved shared profile source.
+// current is an empty object of the profile kind and spec returns the spec of a profile object.
+// A change of source is held back until the rollout of source admits the component of the generated profile,
+// named after it. Manual edits of the generated profile are reverted.
+func (r *Operator) ensureProfile(ctx context.Context, nc *v1alpha1.NeonCluster, source, current, desired client.Object, spec func(client.Object) interface{}, logger *slog.Logger) error {
+	hash, err := k8sutils.CreateInputHash(metav1.ObjectMeta{}, spec(desired))
+	if err != nil {
+		return fmt.Errorf("failed to create input hash for profile %s: %w", desired.GetName(), err)
+	}
+	sourceHash, err := profile.Hash(source)
+	if err != nil {
+		return fmt.Errorf("failed to hash profile %s: %w", source.GetName(), err)
+	}
+
+	err = r.nclient.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	notFound := apierrors.IsNotFound(err)
+	if err != nil && !notFound {
+		return fmt.Errorf("failed to get profile %s: %w", desired.GetName(), err)
+	}
+
+	if !notFound && !ownedBy(current, nc) {
+		return fmt.Errorf("profile %s/%s already exists and is not managed by neoncluster %s", desired.GetNamespace(), desired.GetName(), nc.Name)
+	}
+
+	appliedSourceHash := ""
+	if !notFound {
+		appliedSourceHash = current.GetAnnotations()[profile.SourceProfileHashAnnotationKey]
+	}
+	if !notFound && appliedSourceHash == sourceHash && current.GetAnnotations()[k8sutils.InputHashAnnotationKey] == hash &&
+		equality.Semantic.DeepDerivative(spec(desired), spec(current)) {
+		// No update needed
+		return nil
+	}
+
+	component := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: desired.GetName(), Namespace: desired.GetNamespace()}}
+	if appliedSourceHash != sourceHash && !profile.RolloutAdmitted(source, component, appliedSourceHash, sourceHash) {
+		logger.Info("Waiting for the rollout of the shared profile", "name", desired.GetName(), "profile", source.GetName())
+		return nil
+	}
+
+	operator.UpdateObject(desired,
+		operator.WithAnnotations(map[string]string{
+			k8sutils.InputHashAnnotationKey:        hash,
+			profile.SourceProfileAnnotationKey:     source.GetNamespace() + "/" + source.GetName(),
+			profile.SourceProfileHashAnnotationKey: sourceHash,
+		}),
+		operator.WithLabels(map[string]string{
+			"neoncluster": nc.Name,
+		}),
+		operator.WithOwner(nc),
+	)
+
+	if notFound {
+		if err := r.nclient.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create profile %s: %w", desired.GetName(), err)
+		}
+		logger.Info("Created profile with cluster overrides", "name", desired.GetName())
+		return nil
+	}
+
+	desired.SetResourceVersion(current.GetResourceVersion())
+	if err := r.nclient.Update(ctx, desired); err != nil {
+		return fmt.Errorf("failed to update profile %s: %w", desired.GetName(), err)
+	}
+	logger.Info("Updated profile with cluster overrides", "name", desired.GetName())
+
+	return nil
+}
+
+// deleteGeneratedProfile deletes the profile generated for a component whose overrides were removed.
+// Profiles not owned by the NeonCluster are left alone.
+func (r *Operator) deleteGeneratedProfile(ctx context.Context, nc *v1alpha1.NeonCluster, obj client.Object, name string) error {
+	if err := r.nclient.Get(ctx, client.ObjectKey{Name: name, Namespace: nc.Namespace}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get profile %s: %w", name, err)
+	}
+
+	if !ownedBy(obj, nc) {
+		return nil
+	}
+
+	if err := r.nclient.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete profile %s: %w", name, err)
+	}
+	return nil
+}
+
+// ownedBy reports whether the object has an owner reference to the NeonCluster
+func ownedBy(obj client.Object, nc *v1alpha1.NeonCluster) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == nc.UID {
+			return true
+		}
+	}
+	return false
+}
@@ -25,6 +25,7 @@ import (
 
 	corev1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
 	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/profile"
 )
 
 const (
@@ -35,6 +36,7 @@ const (
 
 // getProfiles fetches all referenced profiles from the NeonCluster spec
 // If a profile is not explicitly referenced, it will attempt to fetch the default profile
+// Profiles are returned with the base profiles they inherit from merged in
 // Returns an error if any referenced or default profile does not exist
 func (r *Operator) getProfiles(ctx context.Context, nc *corev1alpha1.NeonCluster) (*Profiles, error) {
 	profiles := &Profiles{}
@@ -45,17 +47,21 @@ func (r *Operator) getProfiles(ctx context.Context, nc *corev1alpha1.NeonCluster
 		pageServerProfileName = nc.Spec.PageServerProfileRef.Name
 		pageServerNamespace = nc.Spec.PageServerProfileRef.Namespace
 	}
-	profile := &corev1alpha1.PageServerProfile{}
+	psProfile := &corev1alpha1.PageServerProfile{}
 	if err := r.nclient.Get(ctx, client.ObjectKey{
 		Name:      pageServerProfileName,
 		Namespace: pageServerNamespace,
-	}, profile); err != nil {
+	}, psProfile); err != nil {
 		if apierrors.IsNotFound(err) {
 			return nil, fmt.Errorf("PageServerProfile %s/%s not found", pageServerNamespace, pageServerProfileName)
 		}
 		return nil, fmt.Errorf("failed to get PageServerProfile %s/%s: %w", pageServerNamespace, pageServerProfileName, err)
 	}
-	profiles.pageServer = profile.DeepCopy()
+	resolvedPageServerProfile, err := profile.ResolvePageServerProfile(ctx, r.nclient, psProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve PageServerProfile: %w", err)
+	}
+	profiles.pageServer = resolvedPageServerProfile
 
 	safeKeeperProfileName := DefaultSafeKeeperProfileName
 	safeKeeperNamespace := k8sutils.GetOperatorNamespace()
@@ -73,7 +79,11 @@ func (r *Operator) getProfiles(ctx context.Context, nc *corev1alpha1.NeonCluster
 		}
 		return nil, fmt.Errorf("failed to get SafeKeeperProfile %s/%s: %w", safeKeeperNamespace, safeKeeperProfileName, err)
 	}
-	profiles.safeKeeper = skProfile.DeepCopy()
+	resolvedSafeKeeperProfile, err := profile.ResolveSafeKeeperProfile(ctx, r.nclient, skProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve SafeKeeperProfile: %w", err)
+	}
+	profiles.safeKeeper = resolvedSafeKeeperProfile
 
 	storageBrokerProfileName := DefaultStorageBrokerProfileName
 	storageBrokerNamespace := k8sutils.GetOperatorNamespace()
@@ -91,7 +101,11 @@ func (r *Operator) getProfiles(ctx context.Context, nc *corev1alpha1.NeonCluster
 		}
 		return nil, fmt.Errorf("failed to get StorageBrokerProfile %s/%s: %w", storageBrokerNamespace, storageBrokerProfileName, err)
 	}
-	profiles.storageBroker = sbProfile.DeepCopy()
+	resolvedStorageBrokerProfile, err := profile.ResolveStorageBrokerProfile(ctx, r.nclient, sbProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve StorageBrokerProfile: %w", err)
+	}
+	profiles.storageBroker = resolvedStorageBrokerProfile
 
 	return profiles, nil
 }
@@ -0,0 +1,244 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package neoncluster
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+const (
+	// purgeImage runs rclone, which talks to every supported object storage provider
+	purgeImage = "rclone/rclone:1.68"
+	// purgeBackoffLimit is the number of retries of the purge Job before it is reported as failed
+	purgeBackoffLimit = 3
+	// gcsCredentialsPath is where the GCS service account key is mounted in the purge Job
+	gcsCredentialsPath = "/var/secrets/gcs"
+)
+
+// purgeScript deletes everything under $REMOTE. A prefix that was never written to is not an error.
+const purgeScript = `if ! out=$(rclone lsf --max-depth 1 "$REMOTE" 2>&1); then
+  if echo "$out" | grep -q "directory not found"; then
+    echo "nothing to purge under $REMOTE"
+    exit 0
+  fi
+  echo "$out" >&2
+  exit 1
+fi
+exec rclone purge -v "$REMOTE"
+`
+
+// purgeJobName returns the name of the Job purging the object storage prefix of a NeonCluster
+func purgeJobName(nc *v1alpha1.NeonCluster) string {
+	return nc.Name + "-purge"
+}
+
+// purgeObjectStorage deletes the objects under the prefix of the NeonCluster with a Job, when its
+// deletionPolicy is Delete. The Job is not owned by the NeonCluster, which is already being deleted,
+// it is removed once it succeeded. A failed Job blocks the deletion until the policy is set to Retain.
+func (r *Operator) purgeObjectStorage(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (string, error) {
+	if nc.Spec.DeletionPolicy != v1alpha1.DeletionPolicyDelete {
+		return "", nil
+	}
+
+	prefix := strings.Trim(nc.Spec.ObjectStorage.Prefix, "/")
+	if prefix == "" {
+		return "", fmt.Errorf("refusing to purge bucket %s without a prefix", nc.Spec.ObjectStorage.Bucket)
+	}
+
+	name := purgeJobName(nc)
+	job, err := r.kclient.BatchV1().Jobs(nc.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get purge job: %w", err)
+		}
+
+		job, err = makePurgeJob(nc, prefix)
+		if err != nil {
+			return "", err
+		}
+		if _, err := r.kclient.BatchV1().Jobs(nc.Namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("failed to create purge job: %w", err)
+		}
+
+		logger.Info("Created purge job", "name", name, "bucket", nc.Spec.ObjectStorage.Bucket, "prefix", prefix)
+		return fmt.Sprintf("purging %s/%s", nc.Spec.ObjectStorage.Bucket, prefix), nil
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			propagation := metav1.DeletePropagationBackground
+			err := r.kclient.BatchV1().Jobs(nc.Namespace).Delete(ctx, name, metav1.DeleteOptions{
+				PropagationPolicy: &propagation,
+			})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return "", fmt.Errorf("failed to delete purge job: %w", err)
+			}
+			logger.Info("Purged object storage", "bucket", nc.Spec.ObjectStorage.Bucket, "prefix", prefix)
+			return "", nil
+		case batchv1.JobFailed:
+			return "", fmt.Errorf("purge job %s failed: %s, set deletionPolicy to Retain to keep the data and finish the deletion", name, cond.Message)
+		}
+	}
+
+	return fmt.Sprintf("purging %s/%s", nc.Spec.ObjectStorage.Bucket, prefix), nil
+}
+
+// makePurgeJob returns the Job purging the prefix of the NeonCluster.
+// rclone backends are configured on the fly from environment variables, credentials come from
+// the same Secret keys the pageservers and safekeepers use.
+func makePurgeJob(nc *v1alpha1.NeonCluster, prefix string) (*batchv1.Job, error) {
+	storage := nc.Spec.ObjectStorage
+	credentials := storage.CredentialsSecret
+
+	var backend string
+	var env []corev1.EnvVar
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+
+	switch storage.Provider {
+	case "s3", "minio", "local":
+		backend = "s3"
+		provider := "AWS"
+		if storage.Provider != "s3" {
+			provider = "Minio"
+		}
+		env = append(env,
+			corev1.EnvVar{Name: "RCLONE_S3_PROVIDER", Value: provider},
+			corev1.EnvVar{Name: "RCLONE_S3_REGION", Value: storage.Region},
+		)
+		if storage.Endpoint != "" {
+			env = append(env, corev1.EnvVar{Name: "RCLONE_S3_ENDPOINT", Value: storage.Endpoint})
+		}
+		if credentials != nil {
+			env = append(env,
+				secretEnvVar("RCLONE_S3_ACCESS_KEY_ID", credentials.Name, "access-key-id"),
+				secretEnvVar("RCLONE_S3_SECRET_ACCESS_KEY", credentials.Name, "secret-access-key"),
+			)
+		} else {
+			env = append(env, corev1.EnvVar{Name: "RCLONE_S3_ENV_AUTH", Value: "true"})
+		}
+	case "gcs":
+		backend = "gcs"
+		env = append(env, corev1.EnvVar{Name: "RCLONE_GCS_BUCKET_POLICY_ONLY", Value: "true"})
+		if credentials != nil {
+			env = append(env, corev1.EnvVar{Name: "RCLONE_GCS_SERVICE_ACCOUNT_FILE", Value: gcsCredentialsPath + "/service-account.json"})
+			volumes = append(volumes, corev1.Volume{
+				Name: "gcs-credentials",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: credentials.Name,
+					},
+				},
+			})
+			mounts = append(mounts, corev1.VolumeMount{
+				Name:      "gcs-credentials",
+				MountPath: gcsCredentialsPath,
+				ReadOnly:  true,
+			})
+		} else {
+			env = append(env, corev1.EnvVar{Name: "RCLONE_GCS_ENV_AUTH", Value: "true"})
+		}
+	case "azure":
+		backend = "azureblob"
+		if credentials != nil {
+			env = append(env,
+				secretEnvVar("RCLONE_AZUREBLOB_ACCOUNT", credentials.Name, "account-name"),
+				secretEnvVar("RCLONE_AZUREBLOB_KEY", credentials.Name, "account-key"),
+			)
+		} else {
+			env = append(env, corev1.EnvVar{Name: "RCLONE_AZUREBLOB_ENV_AUTH", Value: "true"})
+		}
+	default:
+		return nil, fmt.Errorf("cannot purge object storage provider %q", storage.Provider)
+	}
+
+	env = append(env, corev1.EnvVar{
+		Name:  "REMOTE",
+		Value: fmt.Sprintf(":%s:%s/%s", backend, storage.Bucket, prefix),
+	})
+
+	backoffLimit := int32(purgeBackoffLimit)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      purgeJobName(nc),
+			Namespace: nc.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"neoncluster": nc.Name,
+						"app":         purgeJobName(nc),
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:         "purge",
+							Image:        purgeImage,
+							Command:      []string{"sh", "-c", purgeScript},
+							Env:          env,
+							VolumeMounts: mounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+
+	operator.UpdateObject(job,
+		operator.WithLabels(map[string]string{
+			"neoncluster": nc.Name,
+			"app":         purgeJobName(nc),
+		}),
+	)
+
+	return job, nil
+}
+
+// secretEnvVar returns an environment variable read from a Secret key
+func secretEnvVar(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secret,
+				},
+				Key: key,
+			},
+		},
+	}
+}
@@ -19,13 +19,27 @@ package neoncluster
 import (
 	"context"
 	"fmt"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
 )
 
-const controllerName = "neoncluster-controller"
+const (
+	controllerName = "neoncluster-controller"
+
+	// statusRefreshInterval is how often the status is refreshed while a component rolls out
+	statusRefreshInterval = 15 * time.Second
+)
 
 // +kubebuilder:rbac:groups=core.stateless-pg.io,resources=neonclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core.stateless-pg.io,resources=neonclusters/status,verbs=get;update;patch
@@ -45,21 +59,131 @@ const controllerName = "neoncluster-controller"
 // +kubebuilder:rbac:groups=core.stateless-pg.io,resources=storagebrokers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core.stateless-pg.io,resources=storagebrokers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core.stateless-pg.io,resources=storagebrokers/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets;deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=tenants;timelines,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=computeendpoints,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;create;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
 func (r *Operator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	err := r.sync(ctx, req.Name, req.Namespace)
-	return ctrl.Result{}, fmt.Errorf("Failed to sync neoncluster %s/%s: %w", req.Namespace, req.Name, err)
+	progressing, err := r.sync(ctx, req.Name, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to sync neoncluster %s/%s: %w", req.Namespace, req.Name, err)
+	}
+	if progressing {
+		// Readiness of the pods and the teardown steps are not watched, poll until they complete
+		return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
+	}
+	return ctrl.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Operator) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1alpha1.NeonCluster{}).
+		// Children are owned without a controller reference
+		Owns(&corev1alpha1.PageServer{}, builder.MatchEveryOwner).
+		Owns(&corev1alpha1.SafeKeeper{}, builder.MatchEveryOwner).
+		Owns(&corev1alpha1.StorageBroker{}, builder.MatchEveryOwner).
+		// Profiles generated from cluster overrides
+		Owns(&corev1alpha1.PageServerProfile{}, builder.MatchEveryOwner).
+		Owns(&corev1alpha1.SafeKeeperProfile{}, builder.MatchEveryOwner).
+		Owns(&corev1alpha1.StorageBrokerProfile{}, builder.MatchEveryOwner).
+		Watches(
+			&corev1alpha1.PageServerProfile{},
+			handler.EnqueueRequestsFromMapFunc(r.mapProfileToNeonClusters(DefaultPageServerProfileName,
+				func(nc *corev1alpha1.NeonCluster) *corev1.ObjectReference { return nc.Spec.PageServerProfileRef })),
+		).
+		Watches(
+			&corev1alpha1.SafeKeeperProfile{},
+			handler.EnqueueRequestsFromMapFunc(r.mapProfileToNeonClusters(DefaultSafeKeeperProfileName,
+				func(nc *corev1alpha1.NeonCluster) *corev1.ObjectReference { return nc.Spec.SafeKeeperProfileRef })),
+		).
+		Watches(
+			&corev1alpha1.StorageBrokerProfile{},
+			handler.EnqueueRequestsFromMapFunc(r.mapProfileToNeonClusters(DefaultStorageBrokerProfileName,
+				func(nc *corev1alpha1.NeonCluster) *corev1.ObjectReference { return nc.Spec.StorageBrokerProfileRef })),
+		).
+		// Only the metadata of Secrets is cached, the copies are read and written with the clientset
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecretToNeonClusters),
+			builder.OnlyMetadata,
+		).
 		Named("neoncluster").
 		Complete(r)
 }
+
+// mapProfileToNeonClusters returns a map function from a profile change to all NeonClusters using it.
+// ref returns the profile reference of a NeonCluster, NeonClusters without one use the default profile.
+func (r *Operator) mapProfileToNeonClusters(defaultName string, ref func(*corev1alpha1.NeonCluster) *corev1.ObjectReference) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		return r.listNeonClusters(ctx, "", func(nc *corev1alpha1.NeonCluster) bool {
+			profileRef := ref(nc)
+			if profileRef == nil {
+				return obj.GetName() == defaultName && obj.GetNamespace() == k8sutils.GetOperatorNamespace()
+			}
+			return profileRef.Name == obj.GetName() &&
+				(profileRef.Namespace == "" || profileRef.Namespace == obj.GetNamespace())
+		})
+	}
+}
+
+// mapSecretToNeonClusters maps a change of the control plane secrets to the NeonClusters they are copied to.
+// A change of the source in the operator namespace affects every NeonCluster, a change of a copy only
+// the NeonClusters of its namespace. A change of an issued certificate affects the NeonCluster owning it.
+func (r *Operator) mapSecretToNeonClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "NeonCluster" && ref.APIVersion == corev1alpha1.GroupVersion.String() {
+			return []reconcile.Request{{
+				NamespacedName: types.NamespacedName{
+					Name:      ref.Name,
+					Namespace: obj.GetNamespace(),
+				},
+			}}
+		}
+	}
+
+	switch obj.GetName() {
+	case controlPlaneDefaultSecretName, controlPlaneJWTSecretName, controlplane.CASecretName, controlplane.CACertSecretName:
+	default:
+		return []reconcile.Request{}
+	}
+
+	namespace := obj.GetNamespace()
+	if namespace == k8sutils.GetOperatorNamespace() {
+		namespace = ""
+	}
+
+	return r.listNeonClusters(ctx, namespace, func(*corev1alpha1.NeonCluster) bool { return true })
+}
+
+// listNeonClusters returns a request for every NeonCluster in the namespace, or in all namespaces
+// when it is empty, that matches.
+func (r *Operator) listNeonClusters(ctx context.Context, namespace string, match func(*corev1alpha1.NeonCluster) bool) []reconcile.Request {
+	clusters := &corev1alpha1.NeonClusterList{}
+	if err := r.nclient.List(ctx, clusters, client.InNamespace(namespace)); err != nil {
+		r.logger.Error("failed to list neonclusters", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for i := range clusters.Items {
+		nc := &clusters.Items[i]
+		if match(nc) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      nc.Name,
+					Namespace: nc.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package neoncluster
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+// Progressing reasons of the rollout stages, in rollout order
+const (
+	reasonStorageBrokerRollingOut = "StorageBrokerRollingOut"
+	reasonSafeKeepersRollingOut   = "SafeKeepersRollingOut"
+	reasonPageServersRollingOut   = "PageServersRollingOut"
+)
+
+// rolloutStage is a stage of the rollout the next stages wait for
+type rolloutStage struct {
+	reason  string
+	message string
+}
+
+// storageBrokerStage returns the storage broker stage while the broker is not ready.
+// Safekeepers and pageservers connect to the broker on startup, it must run every pod first.
+func storageBrokerStage(sb *v1alpha1.StorageBroker) *rolloutStage {
+	message := componentNotReady("storagebroker", sb.Name, sb.Generation, &sb.Status.WorkloadStatus, sb.Status.Conditions, sb.Status.Replicas)
+	if message == "" {
+		return nil
+	}
+	return &rolloutStage{reason: reasonStorageBrokerRollingOut, message: message}
+}
+
+// safeKeeperStage returns the safekeeper stage until the safekeepers are rolled out and a quorum is ready.
+// Pageservers only stream WAL from a safekeeper quorum.
+func safeKeeperStage(sk *v1alpha1.SafeKeeper) *rolloutStage {
+	quorum := sk.Status.Replicas/2 + 1
+	message := componentNotReady("safekeeper", sk.Name, sk.Generation, &sk.Status.WorkloadStatus, sk.Status.Conditions, quorum)
+	if message == "" {
+		return nil
+	}
+	return &rolloutStage{reason: reasonSafeKeepersRollingOut, message: message}
+}
+
+// componentNotReady describes why a component is not ready for the next stage, or returns an empty string.
+// The component controller must have observed the latest spec, rolled it out and have minReady ready pods.
+func componentNotReady(kind, name string, generation int64, status *v1alpha1.WorkloadStatus, conditions []metav1.Condition, minReady int32) string {
+	if status.ObservedGeneration < generation {
+		return fmt.Sprintf("%s %s: waiting for the controller to observe the latest spec", kind, name)
+	}
+
+	if cond := meta.FindStatusCondition(conditions, v1alpha1.ComponentConditionDegraded); cond != nil &&
+		cond.Status == metav1.ConditionTrue && cond.Reason == "ReconcileFailed" {
+		return fmt.Sprintf("%s %s: %s", kind, name, cond.Message)
+	}
+
+	if cond := meta.FindStatusCondition(conditions, v1alpha1.ComponentConditionProgressing); cond == nil || cond.Status != metav1.ConditionFalse {
+		return fmt.Sprintf("%s %s: rolling out", kind, name)
+	}
+
+	if status.Replicas == 0 || status.ReadyReplicas < minReady {
+		return fmt.Sprintf("%s %s: %d/%d pods ready, %d needed", kind, name, status.ReadyReplicas, status.Replicas, minReady)
+	}
+
+	return ""
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package neoncluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+// componentState is the observed state of the workload of a component
+type componentState struct {
+	kind   string
+	status v1alpha1.NeonClusterComponentStatus
+	// found is false until the StatefulSet or Deployment exists
+	found bool
+	// rolledOut is true once the latest spec is observed and running on every pod
+	rolledOut bool
+	// minAvailable is the number of ready pods the component needs to serve
+	minAvailable int32
+	// rolloutReason is the Progressing reason while the component rolls out
+	rolloutReason string
+}
+
+// available reports whether the component has enough ready pods to serve
+func (c *componentState) available() bool {
+	return c.found && c.status.Replicas > 0 && c.status.ReadyReplicas >= c.minAvailable
+}
+
+// ready reports whether every pod of the component is ready
+func (c *componentState) ready() bool {
+	return c.found && c.status.ReadyReplicas >= c.status.Replicas
+}
+
+// summary describes the component for condition messages
+func (c *componentState) summary() string {
+	if !c.found {
+		return fmt.Sprintf("%s %s not created", c.kind, c.status.Name)
+	}
+	return fmt.Sprintf("%s %s ready", c.kind, c.status.Ready)
+}
+
+// newComponentState builds the state of a component from the replica counts of its workload
+func newComponentState(kind, name, rolloutReason string, found bool, generation, observedGeneration int64, replicas, ready, updated int32) *componentState {
+	c := &componentState{
+		kind: kind,
+		status: v1alpha1.NeonClusterComponentStatus{
+			Name:            name,
+			Replicas:        replicas,
+			ReadyReplicas:   ready,
+			UpdatedReplicas: updated,
+			Ready:           fmt.Sprintf("%d/%d", ready, replicas),
+		},
+		found:         found,
+		rolledOut:     found && observedGeneration >= generation && updated >= replicas,
+		minAvailable:  replicas,
+		rolloutReason: rolloutReason,
+	}
+	if !found {
+		c.status.Ready = ""
+	}
+	return c
+}
+
+// pageServerState returns the state of the pageserver StatefulSet.
+// Every pageserver must be ready, a missing pod makes the tenants attached to it unavailable.
+func (r *Operator) pageServerState(ctx context.Context, nc *v1alpha1.NeonCluster) (*componentState, error) {
+	name := pageServerName(nc)
+	ss, err := r.kclient.AppsV1().StatefulSets(nc.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return newComponentState("pageserver", name, reasonPageServersRollingOut, false, 0, 0, 0, 0, 0), nil
+		}
+		return nil, fmt.Errorf("failed to get pageserver statefulset: %w", err)
+	}
+
+	return newComponentState("pageserver", name, reasonPageServersRollingOut, true, ss.Generation, ss.Status.ObservedGeneration,
+		operator.ReplicasOrDefault(ss.Spec.Replicas), ss.Status.ReadyReplicas, ss.Status.UpdatedReplicas), nil
+}
+
+// safeKeeperState returns the state of the safekeeper StatefulSet.
+// Safekeepers serve as long as a majority of them is ready.
+func (r *Operator) safeKeeperState(ctx context.Context, nc *v1alpha1.NeonCluster) (*componentState, error) {
+	name := safeKeeperName(nc)
+	ss, err := r.kclient.AppsV1().StatefulSets(nc.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return newComponentState("safekeeper", name, reasonSafeKeepersRollingOut, false, 0, 0, 0, 0, 0), nil
+		}
+		return nil, fmt.Errorf("failed to get safekeeper statefulset: %w", err)
+	}
+
+	replicas := operator.ReplicasOrDefault(ss.Spec.Replicas)
+	c := newComponentState("safekeeper", name, reasonSafeKeepersRollingOut, true, ss.Generation, ss.Status.ObservedGeneration,
+		replicas, ss.Status.ReadyReplicas, ss.Status.UpdatedReplicas)
+	c.minAvailable = replicas/2 + 1
+	return c, nil
+}
+
+// storageBrokerState returns the state of the storage broker Deployment.
+// A single broker pod is enough to serve.
+func (r *Operator) storageBrokerState(ctx context.Context, nc *v1alpha1.NeonCluster) (*componentState, error) {
+	name := storageBrokerName(nc)
+	dep, err := r.kclient.AppsV1().Deployments(nc.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return newComponentState("storagebroker", name, reasonStorageBrokerRollingOut, false, 0, 0, 0, 0, 0), nil
+		}
+		return nil, fmt.Errorf("failed to get storagebroker deployment: %w", err)
+	}
+
+	c := newComponentState("storagebroker", name, reasonStorageBrokerRollingOut, true, dep.Generation, dep.Status.ObservedGeneration,
+		operator.ReplicasOrDefault(dep.Spec.Replicas), dep.Status.ReadyReplicas, dep.Status.UpdatedReplicas)
+	c.minAvailable = 1
+	return c, nil
+}
+
+// updateStatus aggregates the state of the components into the NeonCluster status.
+// stage is the rollout stage the reconcile waits for and syncErr its error, if any. It reports whether
+// a component is rolling out or missing ready pods, until then the status is refreshed periodically.
+func (r *Operator) updateStatus(ctx context.Context, nc *v1alpha1.NeonCluster, stage *rolloutStage, syncErr error) (bool, error) {
+	ps, err := r.pageServerState(ctx, nc)
+	if err != nil {
+		return false, err
+	}
+	sk, err := r.safeKeeperState(ctx, nc)
+	if err != nil {
+		return false, err
+	}
+	sb, err := r.storageBrokerState(ctx, nc)
+	if err != nil {
+		return false, err
+	}
+	components := []*componentState{sb, sk, ps}
+
+	nc.Status.ObservedGeneration = nc.Generation
+	nc.Status.PageServer = &ps.status
+	nc.Status.SafeKeeper = &sk.status
+	nc.Status.StorageBroker = &sb.status
+
+	var unavailable, rollingOut, degraded []string
+	rolloutReason := ""
+	for _, c := range components {
+		if !c.available() {
+			unavailable = append(unavailable, c.summary())
+		}
+		if !c.rolledOut {
+			rollingOut = append(rollingOut, c.summary())
+			if rolloutReason == "" {
+				// Components are in rollout order, the first one rolling out is the current stage
+				rolloutReason = c.rolloutReason
+			}
+		} else if !c.ready() {
+			degraded = append(degraded, c.summary())
+		}
+	}
+
+	available := metav1.Condition{
+		Type:    v1alpha1.NeonClusterConditionAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ComponentsAvailable",
+		Message: "pageservers, safekeeper quorum and storage broker are ready",
+	}
+	if len(unavailable) > 0 {
+		available.Status = metav1.ConditionFalse
+		available.Reason = "ComponentsUnavailable"
+		available.Message = strings.Join(unavailable, ", ")
+	}
+
+	progressing := metav1.Condition{
+		Type:    v1alpha1.NeonClusterConditionProgressing,
+		Status:  metav1.ConditionFalse,
+		Reason:  "RolloutComplete",
+		Message: "every component runs the latest spec",
+	}
+	switch {
+	case stage != nil:
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = stage.reason
+		progressing.Message = stage.message
+	case len(rollingOut) > 0:
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = rolloutReason
+		progressing.Message = strings.Join(rollingOut, ", ")
+	}
+
+	degradedCond := metav1.Condition{
+		Type:    v1alpha1.NeonClusterConditionDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: "no failures",
+	}
+	switch {
+	case syncErr != nil:
+		degradedCond.Status = metav1.ConditionTrue
+		degradedCond.Reason = "ReconcileFailed"
+		degradedCond.Message = syncErr.Error()
+	case len(degraded) > 0:
+		degradedCond.Status = metav1.ConditionTrue
+		degradedCond.Reason = "ReplicasUnavailable"
+		degradedCond.Message = strings.Join(degraded, ", ")
+	}
+
+	for _, cond := range []metav1.Condition{available, progressing, degradedCond} {
+		cond.ObservedGeneration = nc.Generation
+		meta.SetStatusCondition(&nc.Status.Conditions, cond)
+	}
+
+	if err := r.nclient.Status().Update(ctx, nc); err != nil {
+		return false, fmt.Errorf("failed to update neoncluster status: %w", err)
+	}
+
+	return len(rollingOut) > 0 || len(degraded) > 0, nil
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package neoncluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
+	pageserverapi "github.com/stateless-pg/stateless-pg/pkg/pageserver-api"
+	safekeeperapi "github.com/stateless-pg/stateless-pg/pkg/safekeeper-api"
+)
+
+const (
+	// neonClusterFinalizer makes sure the components are shut down in order before the resource is deleted
+	neonClusterFinalizer = "neon.io/neoncluster"
+
+	// walFlushTimeout bounds how long the teardown waits for safekeepers to offload WAL,
+	// counted from the deletion of the NeonCluster
+	walFlushTimeout = 10 * time.Minute
+)
+
+// teardownStep is a step of the NeonCluster teardown.
+// run returns a message while the step is in progress, and an empty one once it is done.
+type teardownStep struct {
+	reason string
+	run    func(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (string, error)
+}
+
+// teardown shuts the NeonCluster down in order and releases the finalizer: computes stop first,
+// then the pageservers detach their tenants, the safekeepers offload WAL and the broker goes last.
+// Each step is idempotent and waits for the previous one. It reports whether the teardown is still in progress.
+func (r *Operator) teardown(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (bool, error) {
+	if !controllerutil.ContainsFinalizer(nc, neonClusterFinalizer) {
+		return false, nil
+	}
+
+	steps := []teardownStep{
+		{reason: "DeletingComputes", run: r.deleteComputes},
+		{reason: "DetachingTenants", run: r.detachTenants},
+		{reason: "DeletingPageServer", run: r.deletePageServer},
+		{reason: "FlushingSafeKeepers", run: r.waitWALOffloaded},
+		{reason: "DeletingSafeKeeper", run: r.deleteSafeKeeper},
+		{reason: "DeletingStorageBroker", run: r.deleteStorageBroker},
+		{reason: "PurgingObjectStorage", run: r.purgeObjectStorage},
+	}
+
+	for _, step := range steps {
+		message, err := step.run(ctx, nc, logger)
+		if err != nil {
+			if statusErr := r.updateTeardownStatus(ctx, nc, step.reason, err.Error(), err); statusErr != nil {
+				logger.Error("failed to update neoncluster status", "error", statusErr)
+			}
+			return false, err
+		}
+		if message != "" {
+			logger.Info("Tearing down neoncluster", "step", step.reason, "message", message)
+			return true, r.updateTeardownStatus(ctx, nc, step.reason, message, nil)
+		}
+	}
+
+	if nc.Spec.DeletionPolicy == v1alpha1.DeletionPolicyDelete {
+		// The remote data is gone, the generations of its tenants are meaningless now
+		cluster := types.NamespacedName{Name: nc.Name, Namespace: nc.Namespace}
+		if err := r.generations.Delete(ctx, cluster); err != nil {
+			return false, err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(nc, neonClusterFinalizer)
+	if err := r.nclient.Update(ctx, nc); err != nil {
+		return false, fmt.Errorf("failed to remove neoncluster finalizer: %w", err)
+	}
+
+	logger.Info("Tore down neoncluster", "deletionPolicy", nc.Spec.DeletionPolicy)
+
+	return false, nil
+}
+
+// deleteComputes deletes the ComputeEndpoints running on the tenants of the NeonCluster
+// and waits until they are gone, so that no WAL is written during the teardown
+func (r *Operator) deleteComputes(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (string, error) {
+	timelines, err := r.clusterTimelines(ctx, nc)
+	if err != nil {
+		return "", err
+	}
+
+	endpoints := &v1alpha1.ComputeEndpointList{}
+	if err := r.nclient.List(ctx, endpoints, client.InNamespace(nc.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list computeendpoints: %w", err)
+	}
+
+	var remaining []string
+	for i := range endpoints.Items {
+		ep := &endpoints.Items[i]
+		if _, ok := timelines[ep.Spec.TimelineRef.Name]; !ok {
+			continue
+		}
+		remaining = append(remaining, ep.Name)
+		if !ep.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.deleteForeground(ctx, ep); err != nil {
+			return "", fmt.Errorf("failed to delete computeendpoint %s: %w", ep.Name, err)
+		}
+		logger.Info("Deleted computeendpoint", "name", ep.Name)
+	}
+
+	if len(remaining) > 0 {
+		return fmt.Sprintf("waiting for computes %s to stop", strings.Join(remaining, ", ")), nil
+	}
+	return "", nil
+}
+
+// detachTenants detaches every attached tenant from its pageserver. A detach shuts the tenant down
+// cleanly, uploading its layers, and keeps its data in remote storage.
+// Detached tenants are recorded in the generation store, which makes the step idempotent.
+func (r *Operator) detachTenants(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (string, error) {
+	tenants, err := r.clusterTenants(ctx, nc)
+	if err != nil {
+		return "", err
+	}
+
+	cluster := types.NamespacedName{Name: nc.Name, Namespace: nc.Namespace}
+	psName := pageServerName(nc)
+
+	var errs []error
+	for _, tn := range tenants {
+		if tn.Spec.TenantID == "" {
+			continue
+		}
+
+		current, err := r.generations.Get(ctx, cluster, tn.Spec.TenantID)
+		if err != nil {
+			return "", err
+		}
+		if current == nil || current.NodeID == controlplane.DetachedNodeID {
+			continue
+		}
+
+		if pod := tn.Status.PageServerPod; pod != "" {
+			_, err := r.kclient.CoreV1().Pods(nc.Namespace).Get(ctx, pod, metav1.GetOptions{})
+			switch {
+			case apierrors.IsNotFound(err):
+				// Nothing runs the tenant anymore, its last uploads are all that is left
+				logger.Info("pageserver pod not found, skipping tenant detach", "tenant", tn.Name, "pod", pod)
+			case err != nil:
+				return "", fmt.Errorf("failed to get pageserver pod: %w", err)
+			default:
+				err := pageserverapi.NewClient(pageserverapi.PodBaseURL(pod, psName, nc.Namespace), controlplane.GetToken(controlplane.ScopePageServerAPI)).
+					LocationConfig(ctx, tn.Spec.TenantID, &pageserverapi.LocationConfig{
+						Mode: pageserverapi.LocationConfigModeDetached,
+					})
+				if err != nil && !errors.Is(err, pageserverapi.ErrNotFound) {
+					errs = append(errs, fmt.Errorf("tenant %s: failed to detach from pageserver %s: %w", tn.Name, pod, err))
+					continue
+				}
+				logger.Info("Detached tenant", "tenant", tn.Name, "pod", pod)
+			}
+		}
+
+		if err := r.generations.Detach(ctx, cluster, tn.Spec.TenantID); err != nil {
+			return "", fmt.Errorf("failed to detach tenant generation: %w", err)
+		}
+	}
+
+	return "", errors.Join(errs...)
+}
+
+// deletePageServer deletes the PageServer once its tenants are detached
+func (r *Operator) deletePageServer(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (string, error) {
+	return r.deleteChild(ctx, &v1alpha1.PageServer{}, pageServerName(nc), nc.Namespace, logger)
+}
+
+// deleteSafeKeeper deletes the SafeKeeper once its WAL is offloaded
+func (r *Operator) deleteSafeKeeper(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (string, error) {
+	return r.deleteChild(ctx, &v1alpha1.SafeKeeper{}, safeKeeperName(nc), nc.Namespace, logger)
+}
+
+// deleteStorageBroker deletes the StorageBroker, the last component to go
+func (r *Operator) deleteStorageBroker(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (string, error) {
+	return r.deleteChild(ctx, &v1alpha1.StorageBroker{}, storageBrokerName(nc), nc.Namespace, logger)
+}
+
+// deleteChild deletes a child resource in the foreground and waits until it is gone,
+// together with its workload and pods
+func (r *Operator) deleteChild(ctx context.Context, obj client.Object, name, namespace string, logger *slog.Logger) (string, error) {
+	if err := r.nclient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get %s: %w", name, err)
+	}
+
+	if obj.GetDeletionTimestamp().IsZero() {
+		if err := r.deleteForeground(ctx, obj); err != nil {
+			return "", fmt.Errorf("failed to delete %s: %w", name, err)
+		}
+		logger.Info("Deleted component", "name", name)
+	}
+
+	return fmt.Sprintf("waiting for %s to be deleted", name), nil
+}
+
+// waitWALOffloaded waits until the safekeepers offloaded the WAL of every timeline of the NeonCluster.
+// WAL is safe once it is either backed up by the safekeepers or persisted by the pageserver in remote storage.
+// The wait is bounded by walFlushTimeout, safekeepers that cannot reach object storage must not block the deletion.
+func (r *Operator) waitWALOffloaded(ctx context.Context, nc *v1alpha1.NeonCluster, logger *slog.Logger) (string, error) {
+	sk := &v1alpha1.SafeKeeper{}
+	if err := r.nclient.Get(ctx, client.ObjectKey{Name: safeKeeperName(nc), Namespace: nc.Namespace}, sk); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get safekeeper: %w", err)
+	}
+
+	if time.Since(nc.DeletionTimestamp.Time) > walFlushTimeout {
+		logger.Warn("Timed out waiting for safekeepers to offload WAL", "timeout", walFlushTimeout)
+		return "", nil
+	}
+
+	timelines, err := r.clusterTimelines(ctx, nc)
+	if err != nil {
+		return "", err
+	}
+
+	var pending []string
+	for _, member := range sk.Status.Members {
+		skClient := safekeeperapi.NewClient("http://"+member.HTTPAddr, controlplane.GetToken(controlplane.ScopeSafekeeperData))
+		for _, tl := range timelines {
+			if tl.Status.TenantID == "" || tl.Status.TimelineID == "" {
+				continue
+			}
+
+			status, err := skClient.TimelineStatus(ctx, tl.Status.TenantID, tl.Status.TimelineID)
+			if errors.Is(err, safekeeperapi.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				pending = append(pending, fmt.Sprintf("%s on %s (%v)", tl.Name, member.Pod, err))
+				continue
+			}
+
+			offloaded, err := walOffloaded(status)
+			if err != nil {
+				return "", fmt.Errorf("timeline %s on %s: %w", tl.Name, member.Pod, err)
+			}
+			if !offloaded {
+				pending = append(pending, fmt.Sprintf("%s on %s", tl.Name, member.Pod))
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Sprintf("waiting for safekeepers to offload WAL of %s", strings.Join(pending, ", ")), nil
+	}
+	return "", nil
+}
+
+// walOffloaded reports whether the committed WAL of a timeline is in remote storage
+func walOffloaded(status *safekeeperapi.TimelineStatus) (bool, error) {
+	commit, err := safekeeperapi.ParseLSN(status.CommitLSN)
+	if err != nil {
+		return false, err
+	}
+	backup, err := safekeeperapi.ParseLSN(status.BackupLSN)
+	if err != nil {
+		return false, err
+	}
+	remoteConsistent, err := safekeeperapi.ParseLSN(status.RemoteConsistentLSN)
+	if err != nil {
+		return false, err
+	}
+
+	return backup >= commit || remoteConsistent >= commit, nil
+}
+
+// clusterTenants returns the Tenants hosted by the NeonCluster
+func (r *Operator) clusterTenants(ctx context.Context, nc *v1alpha1.NeonCluster) ([]v1alpha1.Tenant, error) {
+	tenants := &v1alpha1.TenantList{}
+	if err := r.nclient.List(ctx, tenants, client.InNamespace(nc.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	result := make([]v1alpha1.Tenant, 0, len(tenants.Items))
+	for _, tn := range tenants.Items {
+		if tn.Spec.NeonClusterRef.Name == nc.Name {
+			result = append(result, tn)
+		}
+	}
+	return result, nil
+}
+
+// clusterTimelines returns the Timelines of the tenants hosted by the NeonCluster, by name
+func (r *Operator) clusterTimelines(ctx context.Context, nc *v1alpha1.NeonCluster) (map[string]v1alpha1.Timeline, error) {
+	tenants, err := r.clusterTenants(ctx, nc)
+	if err != nil {
+		return nil, err
+	}
+	tenantNames := make(map[string]bool, len(tenants))
+	for _, tn := range tenants {
+		tenantNames[tn.Name] = true
+	}
+
+	timelines := &v1alpha1.TimelineList{}
+	if err := r.nclient.List(ctx, timelines, client.InNamespace(nc.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list timelines: %w", err)
+	}
+
+	result := make(map[string]v1alpha1.Timeline)
+	for _, tl := range timelines.Items {
+		if tenantNames[tl.Spec.TenantRef.Name] {
+			result[tl.Name] = tl
+		}
+	}
+	return result, nil
+}
+
+// deleteForeground deletes an object once its dependents, such as workloads and pods, are gone
+func (r *Operator) deleteForeground(ctx context.Context, obj client.Object) error {
+	err := r.nclient.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationForeground))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// updateTeardownStatus reports the current teardown step in the Progressing condition
+func (r *Operator) updateTeardownStatus(ctx context.Context, nc *v1alpha1.NeonCluster, reason, message string, syncErr error) error {
+	progressing := metav1.Condition{
+		Type:    v1alpha1.NeonClusterConditionProgressing,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	}
+	degraded := metav1.Condition{
+		Type:    v1alpha1.NeonClusterConditionDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: "no failures",
+	}
+	if syncErr != nil {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = "TeardownFailed"
+		degraded.Message = syncErr.Error()
+	}
+
+	for _, cond := range []metav1.Condition{progressing, degraded} {
+		cond.ObservedGeneration = nc.Generation
+		meta.SetStatusCondition(&nc.Status.Conditions, cond)
+	}
+
+	if err := r.nclient.Status().Update(ctx, nc); err != nil {
+		return fmt.Errorf("failed to update neoncluster status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;patch
+
+// AdoptOrphanedPods relabels the pods left by a previous StatefulSet of the same name, deleted with its pods
+// orphaned, so that the selector of the StatefulSet about to be created matches them. The StatefulSet adopts
+// the pods and rolls them one at a time, a pod it does not match would keep the name of its first pod and stall it.
+// Only the ordinal pods of the StatefulSet without a controller are relabeled.
+func AdoptOrphanedPods(ctx context.Context, kclient kubernetes.Interface, ss *appsv1.StatefulSet) error {
+	selector, err := metav1.LabelSelectorAsSelector(ss.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("failed to parse the selector of statefulset %s: %w", ss.Name, err)
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": ss.Spec.Selector.MatchLabels,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal pod labels patch: %w", err)
+	}
+
+	pods, err := kclient.CoreV1().Pods(ss.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		ordinal, found := strings.CutPrefix(pod.Name, ss.Name+"-")
+		if !found || metav1.GetControllerOf(&pod) != nil || selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if _, err := strconv.ParseUint(ordinal, 10, 32); err != nil {
+			continue
+		}
+		if _, err := kclient.CoreV1().Pods(ss.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to relabel orphaned pod %s: %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
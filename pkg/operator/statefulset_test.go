@@ -0,0 +1,73 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdoptOrphanedPods(t *testing.T) {
+	isController := true
+	pod := func(name string, labels map[string]string, controlled bool) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "neon",
+				Labels:    labels,
+			},
+		}
+		if controlled {
+			p.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "StatefulSet",
+				Name:       "other",
+				Controller: &isController,
+			}}
+		}
+		return p
+	}
+	// Labels of the pods created before the selector was scoped to the PageServer
+	baseline := map[string]string{"app": "pageserver"}
+
+	kclient := fake.NewClientset(
+		pod("ps-0", baseline, false),
+		pod("ps-1", baseline, false),
+		pod("ps-a-0", baseline, false),
+		pod("ps-2", baseline, true),
+	)
+	ss := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ps",
+			Namespace: "neon",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "ps", "component": "pageserver-statefulset"},
+			},
+		},
+	}
+
+	if err := AdoptOrphanedPods(context.Background(), kclient, ss); err != nil {
+		t.Fatalf("AdoptOrphanedPods() error = %v", err)
+	}
+
+	want := map[string]string{
+		"ps-0":   "ps",
+		"ps-1":   "ps",
+		"ps-a-0": "pageserver",
+		"ps-2":   "pageserver",
+	}
+	for name, app := range want {
+		p, err := kclient.CoreV1().Pods("neon").Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get pod %s: %v", name, err)
+		}
+		if p.Labels["app"] != app {
+			t.Errorf("pod %s app label = %q, want %q", name, p.Labels["app"], app)
+		}
+	}
+}
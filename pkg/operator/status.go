@@ -0,0 +1,147 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+// ErrProfileRolloutPending is returned by a reconcile holding back a profile change until the profile
+// rollout admits the component. It is reported as Progressing, not as a failure.
+var ErrProfileRolloutPending = errors.New("waiting for the profile rollout to admit the component")
+
+// ReasonProfileRolloutPending is the Progressing reason of a component holding back a profile change
+const ReasonProfileRolloutPending = "ProfileRolloutPending"
+
+// StatefulSetStatus returns the workload status of a StatefulSet.
+// A nil StatefulSet, not created yet, has an empty status.
+func StatefulSetStatus(ss *appsv1.StatefulSet, generation int64, profileHash string) v1alpha1.WorkloadStatus {
+	status := v1alpha1.WorkloadStatus{
+		ObservedGeneration: generation,
+		ProfileHash:        profileHash,
+	}
+	if ss == nil {
+		return status
+	}
+
+	status.Replicas = ReplicasOrDefault(ss.Spec.Replicas)
+	status.ReadyReplicas = ss.Status.ReadyReplicas
+	status.UpdatedReplicas = ss.Status.UpdatedReplicas
+	status.CurrentRevision = ss.Status.CurrentRevision
+	status.UpdateRevision = ss.Status.UpdateRevision
+	return status
+}
+
+// DeploymentStatus returns the workload status of a Deployment.
+// Deployments do not report revisions in their status, the revision annotation is used for both.
+func DeploymentStatus(dep *appsv1.Deployment, generation int64, profileHash string) v1alpha1.WorkloadStatus {
+	status := v1alpha1.WorkloadStatus{
+		ObservedGeneration: generation,
+		ProfileHash:        profileHash,
+	}
+	if dep == nil {
+		return status
+	}
+
+	status.Replicas = ReplicasOrDefault(dep.Spec.Replicas)
+	status.ReadyReplicas = dep.Status.ReadyReplicas
+	status.UpdatedReplicas = dep.Status.UpdatedReplicas
+	status.UpdateRevision = dep.Annotations["deployment.kubernetes.io/revision"]
+	if status.UpdatedReplicas >= status.Replicas {
+		status.CurrentRevision = status.UpdateRevision
+	}
+	return status
+}
+
+// SetWorkloadConditions sets the Ready, Progressing and Degraded conditions of a component.
+// rolledOut reports whether the workload controller observed the latest spec, syncErr is the
+// error of the reconcile, if any. A workload that was not created yet is only Progressing, as is a
+// workload holding back a profile change.
+func SetWorkloadConditions(conditions *[]metav1.Condition, generation int64, status *v1alpha1.WorkloadStatus, created, rolledOut bool, syncErr error) {
+	replicas := fmt.Sprintf("%d/%d pods ready", status.ReadyReplicas, status.Replicas)
+	allReady := created && status.ReadyReplicas >= status.Replicas
+	updated := created && rolledOut && status.UpdatedReplicas >= status.Replicas
+
+	ready := metav1.Condition{
+		Type:    v1alpha1.ComponentConditionReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  "PodsReady",
+		Message: replicas,
+	}
+	progressing := metav1.Condition{
+		Type:    v1alpha1.ComponentConditionProgressing,
+		Status:  metav1.ConditionFalse,
+		Reason:  "RolloutComplete",
+		Message: fmt.Sprintf("revision %s is rolled out", status.UpdateRevision),
+	}
+	degraded := metav1.Condition{
+		Type:    v1alpha1.ComponentConditionDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  "AsExpected",
+		Message: "no failures",
+	}
+
+	switch {
+	case !created:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "NotCreated"
+		ready.Message = "pods are not created yet"
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = "Creating"
+		progressing.Message = "pods are not created yet"
+	case !updated:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "RollingOut"
+		ready.Message = replicas
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = "RollingOut"
+		progressing.Message = fmt.Sprintf("%d/%d pods updated to revision %s", status.UpdatedReplicas, status.Replicas, status.UpdateRevision)
+	case !allReady:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "PodsNotReady"
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = "PodsNotReady"
+		degraded.Message = replicas
+	}
+
+	if errors.Is(syncErr, ErrProfileRolloutPending) {
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = ReasonProfileRolloutPending
+		progressing.Message = syncErr.Error()
+		syncErr = nil
+	}
+
+	if syncErr != nil {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = "ReconcileFailed"
+		degraded.Message = syncErr.Error()
+	}
+
+	for _, cond := range []metav1.Condition{ready, progressing, degraded} {
+		cond.ObservedGeneration = generation
+		meta.SetStatusCondition(conditions, cond)
+	}
+}
+
+// StatefulSetRolledOut reports whether the StatefulSet controller observed the latest spec
+func StatefulSetRolledOut(ss *appsv1.StatefulSet) bool {
+	return ss != nil && ss.Status.ObservedGeneration >= ss.Generation && ss.Status.CurrentRevision == ss.Status.UpdateRevision
+}
+
+// DeploymentRolledOut reports whether the Deployment controller observed the latest spec
+func DeploymentRolledOut(dep *appsv1.Deployment) bool {
+	return dep != nil && dep.Status.ObservedGeneration >= dep.Generation
+}
+
+// ReplicasOrDefault returns the desired replicas of a workload, which default to 1
+func ReplicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
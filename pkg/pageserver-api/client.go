@@ -0,0 +1,220 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pageserverapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// HTTPPort is the port of the pageserver management API
+	HTTPPort = 9898
+
+	// LocationConfigModeAttachedSingle attaches a tenant shard to exactly one pageserver.
+	LocationConfigModeAttachedSingle = "AttachedSingle"
+	// LocationConfigModeDetached removes a tenant shard from a pageserver, keeping its remote data.
+	LocationConfigModeDetached = "Detached"
+
+	// DefaultStripeSize is the pageserver default shard stripe size, in pages.
+	DefaultStripeSize = 32768
+
+	requestTimeout = 30 * time.Second
+)
+
+// ErrNotFound is returned when the pageserver responds with 404 Not Found.
+var ErrNotFound = errors.New("not found")
+
+// Client talks to the management HTTP API of a single pageserver.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// StatusResponse is the response of GET /v1/status.
+type StatusResponse struct {
+	ID uint64 `json:"id"`
+}
+
+// LocationConfig is the body of PUT /v1/tenant/{tenant_shard_id}/location_config.
+type LocationConfig struct {
+	Mode            string                 `json:"mode"`
+	Generation      *uint32                `json:"generation,omitempty"`
+	SecondaryConf   map[string]interface{} `json:"secondary_conf"`
+	ShardNumber     uint8                  `json:"shard_number"`
+	ShardCount      uint8                  `json:"shard_count"`
+	ShardStripeSize uint32                 `json:"shard_stripe_size"`
+	TenantConf      map[string]interface{} `json:"tenant_conf"`
+}
+
+// TimelineCreateRequest is the body of POST /v1/tenant/{tenant_shard_id}/timeline.
+// A timeline without an ancestor is bootstrapped from an empty database.
+type TimelineCreateRequest struct {
+	NewTimelineID      string  `json:"new_timeline_id"`
+	AncestorTimelineID *string `json:"ancestor_timeline_id,omitempty"`
+	AncestorStartLSN   *string `json:"ancestor_start_lsn,omitempty"`
+	PGVersion          *uint32 `json:"pg_version,omitempty"`
+}
+
+// TimelineInfo is the pageserver view of a timeline.
+type TimelineInfo struct {
+	TenantID           string  `json:"tenant_id"`
+	TimelineID         string  `json:"timeline_id"`
+	AncestorTimelineID *string `json:"ancestor_timeline_id,omitempty"`
+	AncestorLSN        *string `json:"ancestor_lsn,omitempty"`
+	LastRecordLSN      string  `json:"last_record_lsn"`
+	CurrentLogicalSize uint64  `json:"current_logical_size"`
+	PGVersion          uint32  `json:"pg_version"`
+}
+
+// LSNByTimestampResponse is the response of GET .../get_lsn_by_timestamp.
+// Kind is one of present, future, past or nodata.
+type LSNByTimestampResponse struct {
+	LSN  string `json:"lsn"`
+	Kind string `json:"kind"`
+}
+
+// PodBaseURL returns the management API URL of a pageserver pod addressed through its headless service.
+func PodBaseURL(pod, service, namespace string) string {
+	return fmt.Sprintf("http://%s.%s.%s.svc.cluster.local:%d", pod, service, namespace, HTTPPort)
+}
+
+// NewClient creates a client for the pageserver management API at baseURL.
+// token is sent as a bearer token when it is not empty.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: requestTimeout,
+		},
+	}
+}
+
+// Status returns the status of the pageserver, including its node ID.
+func (c *Client) Status(ctx context.Context) (*StatusResponse, error) {
+	status := &StatusResponse{}
+	if err := c.do(ctx, http.MethodGet, "/v1/status", nil, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// LocationConfig attaches, configures or detaches a tenant shard on the pageserver.
+func (c *Client) LocationConfig(ctx context.Context, tenantShardID string, cfg *LocationConfig) error {
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("/v1/tenant/%s/location_config", tenantShardID), cfg, nil)
+}
+
+// DeleteTenant deletes a tenant, including its data in remote storage.
+func (c *Client) DeleteTenant(ctx context.Context, tenantShardID string) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/tenant/%s", tenantShardID), nil, nil)
+}
+
+// CreateTimeline creates a timeline, either bootstrapped or branched from an ancestor timeline.
+func (c *Client) CreateTimeline(ctx context.Context, tenantShardID string, req *TimelineCreateRequest) (*TimelineInfo, error) {
+	info := &TimelineInfo{}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/v1/tenant/%s/timeline", tenantShardID), req, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// GetTimeline returns a timeline, or ErrNotFound if it does not exist.
+func (c *Client) GetTimeline(ctx context.Context, tenantShardID, timelineID string) (*TimelineInfo, error) {
+	info := &TimelineInfo{}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/tenant/%s/timeline/%s", tenantShardID, timelineID), nil, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// DeleteTimeline deletes a timeline, including its data in remote storage.
+// The pageserver refuses to delete timelines that still have child timelines.
+func (c *Client) DeleteTimeline(ctx context.Context, tenantShardID, timelineID string) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/tenant/%s/timeline/%s", tenantShardID, timelineID), nil, nil)
+}
+
+// GetLSNByTimestamp returns the LSN of a timeline at the given point in time.
+func (c *Client) GetLSNByTimestamp(ctx context.Context, tenantShardID, timelineID string, timestamp time.Time) (*LSNByTimestampResponse, error) {
+	query := url.Values{}
+	query.Set("timestamp", timestamp.UTC().Format(time.RFC3339))
+
+	lsn := &LSNByTimestampResponse{}
+	path := fmt.Sprintf("/v1/tenant/%s/timeline/%s/get_lsn_by_timestamp?%s", tenantShardID, timelineID, query.Encode())
+	if err := c.do(ctx, http.MethodGet, path, nil, lsn); err != nil {
+		return nil, err
+	}
+	return lsn, nil
+}
+
+// do sends a request to the pageserver and decodes the JSON response into out when it is not nil
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", method, path, ErrNotFound)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, string(msg))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+
+	return nil
+}
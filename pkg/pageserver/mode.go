@@ -0,0 +1,234 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pageserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+var (
+	// minCheckpointDistance and maxCheckpointDistance bound the WAL a production pageserver keeps before flushing
+	minCheckpointDistance = resource.MustParse("16Mi")
+	maxCheckpointDistance = resource.MustParse("1Gi")
+)
+
+const (
+	// minCheckpointTimeout and maxCheckpointTimeout bound how long a production pageserver delays uploads
+	minCheckpointTimeout = 10 * time.Second
+	maxCheckpointTimeout = 30 * time.Minute
+)
+
+// isDevMode reports whether the profile runs the pageserver in dev mode.
+// Profiles without a mode run in production mode, the CRD default, as do the deprecated modes.
+func isDevMode(psp *v1alpha1.PageServerProfile) bool {
+	return psp.Spec.Mode == v1alpha1.PageServerModeDev
+}
+
+// authType returns the auth type of the pageserver APIs, dev mode always trusts its clients
+func authType(psp *v1alpha1.PageServerProfile) string {
+	if isDevMode(psp) {
+		return noAuth
+	}
+	return psp.Spec.Security.AuthType
+}
+
+// validateProductionProfile checks the guardrails of the production mode.
+// Every violation is reported, the PageServer is not reconciled until they are fixed.
+func validateProductionProfile(ps *v1alpha1.PageServer, psp *v1alpha1.PageServerProfile) error {
+	if isDevMode(psp) {
+		return nil
+	}
+
+	var errs []error
+
+	storage := psp.Spec.Storage
+	if storage == nil || storage.EmptyDir != nil || storage.Ephemeral != nil {
+		errs = append(errs, errors.New("storage must be a volumeClaimTemplate"))
+	}
+
+	if psp.Spec.Security.AuthType != jwtAuth {
+		errs = append(errs, fmt.Errorf("security.authType must be %s", jwtAuth))
+	}
+	if !controlplane.GetEnableJWT() || ps.Spec.JwtPublicKeySecretRef == nil {
+		errs = append(errs, errors.New("JWT must be enabled on the operator and the pageserver must reference the JWT public key"))
+	}
+
+	distance, err := resource.ParseQuantity(psp.Spec.Durability.CheckpointDistance)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid durability.checkpointDistance %q: %w", psp.Spec.Durability.CheckpointDistance, err))
+	} else if distance.Cmp(minCheckpointDistance) < 0 || distance.Cmp(maxCheckpointDistance) > 0 {
+		errs = append(errs, fmt.Errorf("durability.checkpointDistance %s must be between %s and %s",
+			distance.String(), minCheckpointDistance.String(), maxCheckpointDistance.String()))
+	}
+
+	timeout, err := time.ParseDuration(psp.Spec.Durability.CheckpointTimeout)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid durability.checkpointTimeout %q: %w", psp.Spec.Durability.CheckpointTimeout, err))
+	} else if timeout < minCheckpointTimeout || timeout > maxCheckpointTimeout {
+		errs = append(errs, fmt.Errorf("durability.checkpointTimeout %s must be between %s and %s",
+			timeout, minCheckpointTimeout, maxCheckpointTimeout))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("profile violates the production mode guardrails: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// checkpointDistanceBytes returns the checkpoint distance in bytes, as read by the pageserver.
+// The raw value is returned if it is not a quantity, the pageserver reports it.
+func checkpointDistanceBytes(psp *v1alpha1.PageServerProfile) string {
+	distance, err := resource.ParseQuantity(psp.Spec.Durability.CheckpointDistance)
+	if err != nil {
+		return fmt.Sprintf("'%s'", psp.Spec.Durability.CheckpointDistance)
+	}
+	return fmt.Sprintf("%d", distance.Value())
+}
+
+// podAntiAffinity returns the affinity of the pageserver pods.
+// Production pods prefer distinct nodes unless the profile sets its own pod anti-affinity.
+func podAntiAffinity(psp *v1alpha1.PageServerProfile, labels map[string]string) *corev1.Affinity {
+	affinity := psp.Spec.Affinity
+	if isDevMode(psp) || (affinity != nil && affinity.PodAntiAffinity != nil) {
+		return affinity
+	}
+
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	} else {
+		affinity = affinity.DeepCopy()
+	}
+	affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: labels,
+					},
+					TopologyKey: corev1.LabelHostname,
+				},
+			},
+		},
+	}
+	return affinity
+}
+
+// updatePodDisruptionBudget keeps at most one production pageserver pod voluntarily disrupted.
+// Dev pageservers have no PodDisruptionBudget.
+func (o *Operator) updatePodDisruptionBudget(ctx context.Context, ps *v1alpha1.PageServer, psp *v1alpha1.PageServerProfile) error {
+	if isDevMode(psp) {
+		err := o.kclient.PolicyV1().PodDisruptionBudgets(ps.GetNamespace()).Delete(ctx, ps.GetName(), metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pageserver poddisruptionbudget: %w", err)
+		}
+		return nil
+	}
+
+	pdb, err := o.kclient.PolicyV1().PodDisruptionBudgets(ps.GetNamespace()).Get(ctx, ps.GetName(), metav1.GetOptions{})
+	notFound := false
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			notFound = true
+		} else {
+			return fmt.Errorf("failed to get pageserver poddisruptionbudget: %w", err)
+		}
+	}
+
+	newPdb := makePageServerPodDisruptionBudget(ps)
+	hash, err := k8sutils.CreateInputHash(ps.ObjectMeta, newPdb.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to create input hash for pageserver poddisruptionbudget: %w", err)
+	}
+
+	if notFound {
+		if newPdb.Annotations == nil {
+			newPdb.Annotations = make(map[string]string)
+		}
+		newPdb.Annotations[k8sutils.InputHashAnnotationKey] = hash
+
+		_, err = o.kclient.PolicyV1().PodDisruptionBudgets(ps.GetNamespace()).Create(ctx, newPdb, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create pageserver poddisruptionbudget: %w", err)
+		}
+		return nil
+	}
+
+	if pdb.Annotations[k8sutils.InputHashAnnotationKey] == hash && equality.Semantic.DeepDerivative(newPdb.Spec, pdb.Spec) {
+		// No update needed
+		return nil
+	}
+
+	pdb.Spec = newPdb.Spec
+	pdb.Labels = newPdb.Labels
+	if pdb.Annotations == nil {
+		pdb.Annotations = make(map[string]string)
+	}
+	pdb.Annotations[k8sutils.InputHashAnnotationKey] = hash
+
+	_, err = o.kclient.PolicyV1().PodDisruptionBudgets(ps.GetNamespace()).Update(ctx, pdb, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update pageserver poddisruptionbudget: %w", err)
+	}
+
+	return nil
+}
+
+// makePageServerPodDisruptionBudget creates a PodDisruptionBudget for the pods of the PageServer StatefulSet
+func makePageServerPodDisruptionBudget(ps *v1alpha1.PageServer) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt32(1)
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ps.GetName(),
+			Namespace: ps.Namespace,
+			Labels: map[string]string{
+				"app":       ps.GetName(),
+				"component": "pageserver-pdb",
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels(ps),
+			},
+		},
+	}
+
+	operator.UpdateObject(pdb,
+		operator.WithLabels(ps.Labels),
+		operator.WithOwner(ps),
+	)
+
+	return pdb
+}
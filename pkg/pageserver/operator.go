@@ -18,12 +18,16 @@ package pageserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
+	"strconv"
 	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,11 +38,17 @@ import (
 	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
 	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
 	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+	pageserverapi "github.com/stateless-pg/stateless-pg/pkg/pageserver-api"
+	"github.com/stateless-pg/stateless-pg/pkg/profile"
 )
 
 const (
 	jwtAuth = "NeonJWT"
 	noAuth  = "Trust"
+
+	// pgPort is the port of the libpq page service
+	pgPort = 6400
 )
 
 // Operator manages lifecycle for PageServer resources.
@@ -47,6 +57,7 @@ type Operator struct {
 	kclient kubernetes.Interface
 	scheme  *runtime.Scheme
 	logger  *slog.Logger
+	nodes   *controlplane.NodeRegistry
 }
 
 // New creates a new PageServer Operator.
@@ -64,6 +75,7 @@ func New(nclient client.Client, scheme *runtime.Scheme, logger *slog.Logger, con
 		nclient: nclient,
 		kclient: kclient,
 		scheme:  scheme,
+		nodes:   controlplane.NewNodeRegistry(kclient),
 	}, nil
 }
 
@@ -88,77 +100,272 @@ func (o *Operator) sync(ctx context.Context, name, namespace string) error {
 	logger := o.logger.With("key", key)
 	logger.Info("syncing pageserver")
 
-	profile := &v1alpha1.PageServerProfile{}
+	ss, profileHash, syncErr := o.reconcile(ctx, ps)
+
+	// Status is written even when the reconcile failed, Degraded reports the failure
+	if err := o.updateStatus(ctx, ps, ss, profileHash, syncErr); err != nil {
+		if syncErr != nil {
+			logger.Error("failed to update pageserver status", "error", err)
+			return syncErr
+		}
+		return err
+	}
+
+	if errors.Is(syncErr, operator.ErrProfileRolloutPending) {
+		// Requeued by the profile watch once the rollout admits it
+		return nil
+	}
+	return syncErr
+}
+
+// reconcile creates or updates the children of the PageServer.
+// It returns the StatefulSet, nil while it is being recreated, and the hash of the applied profile.
+func (o *Operator) reconcile(ctx context.Context, ps *v1alpha1.PageServer) (*appsv1.StatefulSet, string, error) {
+	psp := &v1alpha1.PageServerProfile{}
 	if err := o.nclient.Get(ctx, client.ObjectKey{
 		Name:      ps.Spec.ProfileRef.Name,
 		Namespace: ps.Spec.ProfileRef.Namespace,
-	}, profile); err != nil {
-		return fmt.Errorf("failed to get pageserver profile : %w", err)
+	}, psp); err != nil {
+		return nil, "", fmt.Errorf("failed to get pageserver profile : %w", err)
+	}
+
+	// Merge the base profiles the profile inherits from
+	psp, err := profile.ResolvePageServerProfile(ctx, o.nclient, psp)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve pageserver profile: %w", err)
+	}
+
+	profileHash, err := profile.Hash(psp)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create input hash for pageserver profile: %w", err)
+	}
+
+	// A change of a profile with a rollout policy waits until the rollout admits this PageServer
+	if !profile.RolloutAdmitted(psp, ps, ps.Status.ProfileHash, profileHash) {
+		ss, err := o.kclient.AppsV1().StatefulSets(ps.GetNamespace()).Get(ctx, ps.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, ps.Status.ProfileHash, fmt.Errorf("failed to get pageserver statefulset: %w", err)
+		}
+		return ss, ps.Status.ProfileHash, operator.ErrProfileRolloutPending
 	}
 
-	profile = profile.DeepCopy()
+	if err := validateProductionProfile(ps, psp); err != nil {
+		return nil, profileHash, err
+	}
 
 	// Check if TLS is enabled in StorageBrokerProfile
 	storageBrokerTLSEnabled, err := o.isStorageBrokerTLSEnabled(ctx, ps)
 	if err != nil {
-		return fmt.Errorf("failed to check storagebroker tls status: %w", err)
+		return nil, profileHash, fmt.Errorf("failed to check storagebroker tls status: %w", err)
 	}
 
 	if err := o.updateHeadlessService(ctx, ps); err != nil {
-		return fmt.Errorf("failed to reconcile pageserver headless service: %w", err)
+		return nil, profileHash, fmt.Errorf("failed to reconcile pageserver headless service: %w", err)
 	}
 
-	if err := o.createPageServerConfigMap(ctx, ps, profile, storageBrokerTLSEnabled); err != nil {
-		return fmt.Errorf("failed to create pageserver configmap: %w", err)
+	// The token is rendered into a copy of pageserver.toml in memory on startup, neither the ConfigMap nor
+	// the workdir hold it. It authenticates the upcalls of the pageserver to the control plane.
+	tokenExpiresAt, err := controlplane.UpdateTokenSecret(ctx, o.kclient, ps,
+		fmt.Sprintf("pageserver/%s/%s", ps.GetNamespace(), ps.GetName()), controlplane.ScopeGenerationsAPI,
+		map[string]string{
+			"app":       ps.GetName(),
+			"component": "pageserver-token",
+		})
+	if err != nil {
+		return nil, profileHash, fmt.Errorf("failed to reconcile pageserver token secret: %w", err)
 	}
 
-	if err := o.updateStatefulSet(ctx, ps, profile); err != nil {
-		return fmt.Errorf("failed to reconcile pageserver statefulset: %w", err)
+	configHash, err := o.createPageServerConfigMap(ctx, ps, psp, storageBrokerTLSEnabled)
+	if err != nil {
+		return nil, profileHash, fmt.Errorf("failed to create pageserver configmap: %w", err)
 	}
 
+	// Node IDs must exist before the pods that read them are created
+	nodes, err := o.updateNodeIDs(ctx, ps, psp)
+	if err != nil {
+		return nil, profileHash, fmt.Errorf("failed to reconcile pageserver node ids: %w", err)
+	}
+
+	ps.Status.Nodes = make([]v1alpha1.PageServerNode, 0, len(nodes))
+	for _, node := range nodes {
+		ps.Status.Nodes = append(ps.Status.Nodes, v1alpha1.PageServerNode{
+			Pod:            node.PodName(),
+			NodeID:         int64(node.NodeID),
+			ListenPgAddr:   node.ListenPgAddr,
+			ListenHTTPAddr: node.ListenHTTPAddr,
+		})
+	}
+
+	// pageserver.toml, the token and the public keys are read on startup, their changes roll the pods
+	podAnnotations := map[string]string{
+		k8sutils.ConfigHashAnnotationKey:         configHash,
+		controlplane.TokenExpiresAtAnnotationKey: tokenExpiresAt,
+	}
+	if ps.Spec.JwtPublicKeySecretRef != nil {
+		keyIDs, err := controlplane.TrustedKeyIDs(ctx, o.kclient, ps.Namespace, ps.Spec.JwtPublicKeySecretRef)
+		if err != nil {
+			return nil, profileHash, err
+		}
+		podAnnotations[controlplane.JWTKeyIDsAnnotationKey] = keyIDs
+	}
+
+	ss, err := o.updateStatefulSet(ctx, ps, psp, podAnnotations)
+	if err != nil {
+		return nil, profileHash, fmt.Errorf("failed to reconcile pageserver statefulset: %w", err)
+	}
+
+	if err := o.updatePodDisruptionBudget(ctx, ps, psp); err != nil {
+		return nil, profileHash, fmt.Errorf("failed to reconcile pageserver poddisruptionbudget: %w", err)
+	}
+
+	return ss, profileHash, nil
+}
+
+// updateStatus persists the workload status and conditions of the PageServer
+func (o *Operator) updateStatus(ctx context.Context, ps *v1alpha1.PageServer, ss *appsv1.StatefulSet, profileHash string, syncErr error) error {
+	if profileHash == "" {
+		// The profile could not be read, keep reporting the last applied one
+		profileHash = ps.Status.ProfileHash
+	}
+
+	ps.Status.WorkloadStatus = operator.StatefulSetStatus(ss, ps.Generation, profileHash)
+	operator.SetWorkloadConditions(&ps.Status.Conditions, ps.Generation, &ps.Status.WorkloadStatus,
+		ss != nil, operator.StatefulSetRolledOut(ss), syncErr)
+
+	if err := o.nclient.Status().Update(ctx, ps); err != nil {
+		return fmt.Errorf("failed to update pageserver status: %w", err)
+	}
 	return nil
 }
 
-func (o *Operator) updateStatefulSet(ctx context.Context, ps *v1alpha1.PageServer, profile *v1alpha1.PageServerProfile) error {
+// updateNodeIDs allocates a node ID for every pod of the PageServer in the control plane node registry
+// and publishes them in a ConfigMap keyed by pod name, read by the identity init container.
+func (o *Operator) updateNodeIDs(ctx context.Context, ps *v1alpha1.PageServer, profile *v1alpha1.PageServerProfile) ([]controlplane.RegisteredNode, error) {
+	nodes, err := o.nodes.Allocate(ctx, client.ObjectKeyFromObject(ps), pageServerReplicas(profile), func(ordinal int32) (string, string) {
+		host := fmt.Sprintf("%s-%d.%s.%s.svc.cluster.local", ps.Name, ordinal, ps.Name, ps.Namespace)
+		return fmt.Sprintf("%s:%d", host, pgPort), fmt.Sprintf("%s:%d", host, pageserverapi.HTTPPort)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		data[node.PodName()] = strconv.FormatUint(node.NodeID, 10)
+	}
+
+	name := NodeIDsConfigMapName(ps.Name)
+	cm, err := o.kclient.CoreV1().ConfigMaps(ps.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get pageserver node ids configmap: %w", err)
+		}
+
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ps.Namespace,
+				Labels: map[string]string{
+					"app":       ps.Name,
+					"component": "pageserver-node-ids",
+				},
+			},
+			Data: data,
+		}
+		operator.UpdateObject(cm, operator.WithOwner(ps))
+
+		if _, err := o.kclient.CoreV1().ConfigMaps(ps.Namespace).Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return nil, fmt.Errorf("failed to create pageserver node ids configmap: %w", err)
+		}
+		return nodes, nil
+	}
+
+	if maps.Equal(cm.Data, data) {
+		return nodes, nil
+	}
+
+	cm.Data = data
+	if _, err := o.kclient.CoreV1().ConfigMaps(ps.Namespace).Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to update pageserver node ids configmap: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// updateStatefulSet creates or updates the PageServer StatefulSet.
+// podAnnotations are set on the pod template, their changes roll the pods.
+func (o *Operator) updateStatefulSet(ctx context.Context, ps *v1alpha1.PageServer, profile *v1alpha1.PageServerProfile, podAnnotations map[string]string) (*appsv1.StatefulSet, error) {
 	ss, err := o.kclient.AppsV1().StatefulSets(ps.GetNamespace()).Get(ctx, ps.GetName(), metav1.GetOptions{})
 	notFound := false
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			notFound = true
 		} else {
-			return fmt.Errorf("failed to get pageserver statefulset: %w", err)
+			return nil, fmt.Errorf("failed to get pageserver statefulset: %w", err)
 		}
 	}
 
-	spec, err := makePageServerStatefulSetSpec(ps, profile)
+	spec, err := makePageServerStatefulSetSpec(ps, profile, podAnnotations)
 	if err != nil {
-		return fmt.Errorf("failed to create pageserver statefulset spec: %w", err)
+		return nil, fmt.Errorf("failed to create pageserver statefulset spec: %w", err)
 	}
 	sset, err := makePageServerStatefulSet(ps, spec)
 	if err != nil {
-		return fmt.Errorf("failed to create pageserver statefulset object: %w", err)
+		return nil, fmt.Errorf("failed to create pageserver statefulset object: %w", err)
 	}
 	hash, err := k8sutils.CreateInputHash(ps.ObjectMeta, spec)
 	if err != nil {
-		return fmt.Errorf("failed to create input hash for pageserver statefulset: %w", err)
+		return nil, fmt.Errorf("failed to create input hash for pageserver statefulset: %w", err)
 	}
 
 	if notFound {
-		if ss.Annotations == nil {
-			ss.Annotations = make(map[string]string)
+		if sset.Annotations == nil {
+			sset.Annotations = make(map[string]string)
 		}
-		ss.Annotations[k8sutils.InputHashAnnotationKey] = hash
+		sset.Annotations[k8sutils.InputHashAnnotationKey] = hash
 
-		_, err = o.kclient.AppsV1().StatefulSets(ps.GetNamespace()).Create(ctx, ss, metav1.CreateOptions{})
+		if err := operator.AdoptOrphanedPods(ctx, o.kclient, sset); err != nil {
+			return nil, fmt.Errorf("failed to adopt orphaned pageserver pods: %w", err)
+		}
+		created, err := o.kclient.AppsV1().StatefulSets(ps.GetNamespace()).Create(ctx, sset, metav1.CreateOptions{})
 		if err != nil {
-			return fmt.Errorf("failed to create pageserver statefulset: %w", err)
+			return nil, fmt.Errorf("failed to create pageserver statefulset: %w", err)
 		}
-		return nil
+		return created, nil
 	}
 
 	if ss.Annotations[k8sutils.InputHashAnnotationKey] == hash {
 		// No update needed
-		return nil
+		return ss, nil
+	}
+
+	if !equality.Semantic.DeepEqual(ss.Spec.Selector, sset.Spec.Selector) {
+		// The selector is immutable. The StatefulSet is recreated on the next reconcile.
+		// Its pods and volume claims are orphaned, the pods are relabeled with the new selector before the new
+		// StatefulSet is created, which adopts and rolls them.
+		o.logger.Warn("pageserver statefulset selector changed, recreating it", "name", ss.Name, "namespace", ss.Namespace)
+		propagation := metav1.DeletePropagationOrphan
+		err = o.kclient.AppsV1().StatefulSets(ps.GetNamespace()).Delete(ctx, ss.Name, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete pageserver statefulset: %w", err)
+		}
+		return nil, nil
+	}
+
+	if volumeClaimTemplatesChanged(ss, sset) {
+		// Switching between dev and production mode adds or removes the volume claim template, which cannot be updated.
+		// The StatefulSet is recreated on the next reconcile, its pods are orphaned and rolled onto the new storage.
+		o.logger.Warn("pageserver statefulset volume claim templates changed, recreating it", "name", ss.Name, "namespace", ss.Namespace)
+		propagation := metav1.DeletePropagationOrphan
+		err = o.kclient.AppsV1().StatefulSets(ps.GetNamespace()).Delete(ctx, ss.Name, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete pageserver statefulset: %w", err)
+		}
+		return nil, nil
 	}
 
 	ss.Spec = sset.Spec
@@ -169,12 +376,19 @@ func (o *Operator) updateStatefulSet(ctx context.Context, ps *v1alpha1.PageServe
 	maps.Copy(ss.Annotations, sset.Annotations)
 	ss.Annotations[k8sutils.InputHashAnnotationKey] = hash
 
-	_, err = o.kclient.AppsV1().StatefulSets(ps.GetNamespace()).Update(ctx, ss, metav1.UpdateOptions{})
+	updated, err := o.kclient.AppsV1().StatefulSets(ps.GetNamespace()).Update(ctx, ss, metav1.UpdateOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to update pageserver statefulset: %w", err)
+		return nil, fmt.Errorf("failed to update pageserver statefulset: %w", err)
 	}
 
-	return nil
+	return updated, nil
+}
+
+// volumeClaimTemplatesChanged reports whether the desired volume claim templates differ from the ones of the StatefulSet.
+// The fields defaulted by the API server are ignored.
+func volumeClaimTemplatesChanged(current, desired *appsv1.StatefulSet) bool {
+	return len(current.Spec.VolumeClaimTemplates) != len(desired.Spec.VolumeClaimTemplates) ||
+		!equality.Semantic.DeepDerivative(desired.Spec.VolumeClaimTemplates, current.Spec.VolumeClaimTemplates)
 }
 
 func (o *Operator) updateHeadlessService(ctx context.Context, ps *v1alpha1.PageServer) error {
@@ -258,65 +472,92 @@ func (o *Operator) isStorageBrokerTLSEnabled(ctx context.Context, ps *v1alpha1.P
 		return false, fmt.Errorf("failed to get storagebroker profile: %w", err)
 	}
 
+	sbProf, err := profile.ResolveStorageBrokerProfile(ctx, o.nclient, sbProf)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve storagebroker profile: %w", err)
+	}
+
 	return sbProf.Spec.EnableTLS, nil
 }
 
-func (o *Operator) createPageServerConfigMap(ctx context.Context, ps *v1alpha1.PageServer, psp *v1alpha1.PageServerProfile, storageBrokerTLSEnabled bool) error {
+// createPageServerConfigMap creates or updates the ConfigMap holding pageserver.toml.
+// It returns the hash of the rendered config, set on the pod template so that a config change restarts the pods.
+func (o *Operator) createPageServerConfigMap(ctx context.Context, ps *v1alpha1.PageServer, psp *v1alpha1.PageServerProfile, storageBrokerTLSEnabled bool) (string, error) {
 	configMapName := ps.GetName() + "-config"
 	namespace := ps.GetNamespace()
 
+	data := map[string]string{
+		"pageserver.toml": generatePageServerToml(ps, psp, storageBrokerTLSEnabled),
+	}
+	configHash, err := k8sutils.CreateInputHash(metav1.ObjectMeta{}, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to create input hash for pageserver config: %w", err)
+	}
+
 	cm := &corev1.ConfigMap{}
 	if err := o.nclient.Get(ctx, client.ObjectKey{
 		Name:      configMapName,
 		Namespace: namespace,
 	}, cm); err != nil {
 		if !apierrors.IsNotFound(err) {
-			return fmt.Errorf("failed to get pageserver configmap: %w", err)
+			return "", fmt.Errorf("failed to get pageserver configmap: %w", err)
 		}
 		// Create new configmap
-		tomlContent := generatePageServerToml(ps, psp, storageBrokerTLSEnabled)
 		cm = &corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      configMapName,
 				Namespace: namespace,
 				Labels: map[string]string{
-					"app":       "pageserver",
+					"app":       ps.GetName(),
 					"component": "pageserver-config",
 				},
 			},
-			Data: map[string]string{
-				"pageserver.toml": tomlContent,
-			},
+			Data: data,
 		}
+		operator.UpdateObject(cm, operator.WithOwner(ps))
 
 		if err := o.nclient.Create(ctx, cm); err != nil {
-			return fmt.Errorf("failed to create pageserver configmap: %w", err)
+			return "", fmt.Errorf("failed to create pageserver configmap: %w", err)
+		}
+		return configHash, nil
+	}
+
+	// ConfigMaps created before they had an owner are adopted
+	owned := false
+	for _, ref := range cm.GetOwnerReferences() {
+		if ref.UID == ps.GetUID() {
+			owned = true
+			break
 		}
-		return nil
+	}
+
+	if owned && maps.Equal(cm.Data, data) {
+		// No update needed
+		return configHash, nil
 	}
 
 	// Update existing configmap
-	tomlContent := generatePageServerToml(ps, psp, storageBrokerTLSEnabled)
-	cm.Data = map[string]string{
-		"pageserver.toml": tomlContent,
+	cm.Data = data
+	if !owned {
+		operator.UpdateObject(cm, operator.WithOwner(ps))
 	}
 
 	if err := o.nclient.Update(ctx, cm); err != nil {
-		return fmt.Errorf("failed to update pageserver configmap: %w", err)
+		return "", fmt.Errorf("failed to update pageserver configmap: %w", err)
 	}
 
-	return nil
+	return configHash, nil
 }
 
 func generatePageServerToml(ps *v1alpha1.PageServer, psp *v1alpha1.PageServerProfile, storageBrokerTLSEnabled bool) string {
 	var sb strings.Builder
 
 	// Control plane settings
-	sb.WriteString(fmt.Sprintf("control_plane_api = '%s'\n", fmt.Sprintf("%s://%s.%s.svc.cluster.local:%s", controlplane.GetProtocol(), controlplane.ServiceName, k8sutils.GetOperatorNamespace(), controlplane.GetPort())))
+	sb.WriteString(fmt.Sprintf("control_plane_api = '%s'\n", controlplane.GetServiceURL()+controlplane.UpcallPath()))
 	sb.WriteString(fmt.Sprintf("control_plane_emergency_mode = '%t'\n", psp.Spec.ControlPlane.EmergencyMode))
 
 	if controlplane.GetEnableTLS() {
-		sb.WriteString(fmt.Sprintf("ssl_ca_certs = %s\n", TLSCertPath))
+		sb.WriteString(fmt.Sprintf("ssl_ca_certs = '%s'\n", CACertPath))
 	}
 
 	neonClusterName := ps.Labels["neoncluster"]
@@ -331,7 +572,7 @@ func generatePageServerToml(ps *v1alpha1.PageServer, psp *v1alpha1.PageServerPro
 	sb.WriteString(fmt.Sprintf("listen_pg_addr = '%s'\n", "0.0.0.0:6400"))
 	sb.WriteString(fmt.Sprintf("http_listen_addr = '%s'\n", "0.0.0.0:9898"))
 
-	if controlplane.GetJWTToken() != "" {
+	if controlplane.GetEnableJWT() && !isDevMode(psp) {
 		sb.WriteString(fmt.Sprintf("http_auth_type = '%s'\n", jwtAuth))
 	} else {
 		sb.WriteString(fmt.Sprintf("http_auth_type = '%s'\n", noAuth))
@@ -344,7 +585,7 @@ func generatePageServerToml(ps *v1alpha1.PageServer, psp *v1alpha1.PageServerPro
 		sb.WriteString(fmt.Sprintf("ssl_key_file = '%s'\n", TLSKeyPath))
 	}
 
-	sb.WriteString(fmt.Sprintf("checkpoint_distance = '%s'\n", psp.Spec.Durability.CheckpointDistance))
+	sb.WriteString(fmt.Sprintf("checkpoint_distance = %s\n", checkpointDistanceBytes(psp)))
 
 	sb.WriteString(fmt.Sprintf("checkpoint_timeout = '%s'\n", psp.Spec.Durability.CheckpointTimeout))
 
@@ -354,20 +595,23 @@ func generatePageServerToml(ps *v1alpha1.PageServer, psp *v1alpha1.PageServerPro
 
 	sb.WriteString(fmt.Sprintf("pitr_interval = '%s'\n", psp.Spec.Retention.PITRRetention))
 
-	sb.WriteString(fmt.Sprintf("ingest_batch_size = %d\n", *psp.Spec.Performance.IngestBatchSize))
+	if psp.Spec.Performance.IngestBatchSize != nil {
+		sb.WriteString(fmt.Sprintf("ingest_batch_size = %d\n", *psp.Spec.Performance.IngestBatchSize))
+	}
 
 	sb.WriteString(fmt.Sprintf("virtual_file_io_mode = %s\n", psp.Spec.Performance.IOMode))
 
 	sb.WriteString(fmt.Sprintf("log_level = '%s'\n", strings.ToLower(psp.Spec.Observability.LogLevel)))
 
-	sb.WriteString(fmt.Sprintf("pg_auth_type = '%s'\n", psp.Spec.Security.AuthType))
-	sb.WriteString(fmt.Sprintf("grpc_auth_type = '%s'\n", psp.Spec.Security.AuthType))
+	sb.WriteString(fmt.Sprintf("pg_auth_type = '%s'\n", authType(psp)))
+	sb.WriteString(fmt.Sprintf("grpc_auth_type = '%s'\n", authType(psp)))
 
-	if controlplane.GetJWTToken() != "" {
-		sb.WriteString(fmt.Sprintf("control_plane_api_token = '%s'\n", controlplane.GetJWTToken()))
+	// Dev mode trades durability for speed, the local disk is not synced
+	if isDevMode(psp) {
+		sb.WriteString("no_sync = true\n")
 	}
 
-	if controlplane.GetEnableJWT() {
+	if controlplane.GetEnableJWT() && !isDevMode(psp) {
 		sb.WriteString(fmt.Sprintf("auth_validation_public_key_path = '%s'\n", PublicKeyPath))
 	}
 
@@ -21,8 +21,10 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -39,6 +41,9 @@ const controllerName = "pageserver-controller"
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -54,13 +59,22 @@ func (r *Operator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result
 // SetupWithManager sets up the controller with the Manager.
 func (r *Operator) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
+		// Children are owned without a controller reference
 		For(&corev1alpha1.PageServer{}).
-		Owns(&appsv1.StatefulSet{}).
-		Owns(&corev1.Service{}).
+		Owns(&appsv1.StatefulSet{}, builder.MatchEveryOwner).
+		Owns(&corev1.Service{}, builder.MatchEveryOwner).
+		Owns(&corev1.ConfigMap{}, builder.MatchEveryOwner).
+		Owns(&corev1.Secret{}, builder.MatchEveryOwner).
+		Owns(&policyv1.PodDisruptionBudget{}, builder.MatchEveryOwner).
 		Watches(
 			&corev1alpha1.PageServerProfile{},
 			handler.EnqueueRequestsFromMapFunc(r.mapPageServerProfileToPageServers),
 		).
+		// The pods trust the public keys of the copied JWT keys Secret
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapJWTKeysSecretToPageServers),
+		).
 		Named("pageserver").
 		Complete(r)
 }
@@ -96,3 +110,27 @@ func (r *Operator) mapPageServerProfileToPageServers(ctx context.Context, obj cl
 
 	return requests
 }
+
+// mapJWTKeysSecretToPageServers maps a JWT public keys Secret change to all PageServers that reference it.
+func (r *Operator) mapJWTKeysSecretToPageServers(ctx context.Context, obj client.Object) []reconcile.Request {
+	pageservers := &corev1alpha1.PageServerList{}
+	if err := r.nclient.List(ctx, pageservers, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.logger.Error("failed to list pageservers", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, ps := range pageservers.Items {
+		ref := ps.Spec.JwtPublicKeySecretRef
+		if ref != nil && ref.Name == obj.GetName() && (ref.Namespace == "" || ref.Namespace == obj.GetNamespace()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      ps.Name,
+					Namespace: ps.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}
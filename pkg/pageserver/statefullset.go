@@ -18,6 +18,7 @@ package pageserver
 
 import (
 	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
 	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
 	"github.com/stateless-pg/stateless-pg/pkg/operator"
 	appsv1 "k8s.io/api/apps/v1"
@@ -29,13 +30,76 @@ const (
 	TLSCertPath   = "/etc/pageserver/certs/tls.crt"
 	TLSKeyPath    = "/etc/pageserver/certs/tls.key"
 	tlsVolumeName = "tls-certs"
-	PublicKeyPath = "/etc/pageserver/certs/jwt.pub"
+	// CACertPath is the CA certificate verifying the control plane and the other services
+	CACertPath       = caCertDir + "/" + controlplane.CACertKey
+	caCertDir        = "/etc/pageserver/ca"
+	caCertVolumeName = "ca-cert"
+	// PublicKeyPath is the directory of the trusted JWT public keys, the pageserver loads every key in it
+	PublicKeyPath = "/etc/pageserver/jwt"
+	// TokenPath is the file of the control plane token in the init container, projected from the token Secret
+	TokenPath       = tokenDir + "/" + controlplane.TokenSecretKey
+	tokenDir        = "/etc/pageserver/token"
+	tokenVolumeName = "token"
+
+	dataPath    = "/data"
+	workDir     = dataPath + "/.neon"
+	configPath  = "/config"
+	nodeIDsPath = "/node-ids"
+	// runtimeConfigPath is an in-memory volume holding pageserver.toml with the token rendered into it
+	runtimeConfigPath       = "/etc/pageserver/runtime"
+	runtimeConfigVolumeName = "runtime-config"
 )
 
+// identityScript waits for the node ID of the pod and writes the pageserver workdir.
+// The node ID is allocated before the pod is created, waiting only covers a stale ConfigMap volume.
+// pageserver.toml is rendered with the control plane token into the in-memory volume, the workdir
+// only links to it so that the token is never written to the data volume.
+const identityScript = `set -e
+id_file="` + nodeIDsPath + `/$(hostname)"
+until [ -s "$id_file" ]; do
+  echo "waiting for node id in $id_file"
+  sleep 2
+done
+cp ` + configPath + `/pageserver.toml ` + runtimeConfigPath + `/pageserver.toml
+if [ -s ` + TokenPath + ` ]; then
+  echo "control_plane_api_token = '$(cat ` + TokenPath + `)'" >> ` + runtimeConfigPath + `/pageserver.toml
+fi
+mkdir -p ` + workDir + `
+ln -sf ` + runtimeConfigPath + `/pageserver.toml ` + workDir + `/pageserver.toml
+echo "id=$(cat "$id_file")" > ` + workDir + `/identity.toml
+`
+
+// NodeIDsConfigMapName returns the name of the ConfigMap holding the node ID of each pod of a PageServer.
+func NodeIDsConfigMapName(psName string) string {
+	return psName + "-node-ids"
+}
+
+// pageServerReplicas returns the number of pageserver pods, MinReplicas is used as the desired count.
+// Dev mode runs a single pod.
+func pageServerReplicas(psp *v1alpha1.PageServerProfile) int32 {
+	replicas := int32(1)
+	if psp.Spec.MinReplicas != nil && !isDevMode(psp) {
+		replicas = int32(*psp.Spec.MinReplicas)
+	}
+	return replicas
+}
+
+// podLabels returns the labels of the pageserver pods, scoped to the PageServer so that several clusters can share a namespace
+func podLabels(ps *v1alpha1.PageServer) map[string]string {
+	return map[string]string{
+		"app":       ps.GetName(),
+		"component": "pageserver-statefulset",
+	}
+}
+
 // makePageServerStatefulSet creates a StatefulSet for the Page Server component
 func makePageServerStatefulSet(ps *v1alpha1.PageServer, spec *appsv1.StatefulSetSpec) (*appsv1.StatefulSet, error) {
 
 	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ps.Name,
+			Namespace: ps.Namespace,
+		},
 		Spec: *spec,
 	}
 
@@ -47,7 +111,9 @@ func makePageServerStatefulSet(ps *v1alpha1.PageServer, spec *appsv1.StatefulSet
 	return statefulSet, nil
 }
 
-func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageServerProfile) (*appsv1.StatefulSetSpec, error) {
+// makePageServerStatefulSetSpec creates the StatefulSet spec of the PageServer.
+// The pods are annotated with podAnnotations, the init container only reads pageserver.toml and the token at startup.
+func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageServerProfile, podAnnotations map[string]string) (*appsv1.StatefulSetSpec, error) {
 	psName := ps.GetName()
 	cpf := psp.Spec.CommonFields
 
@@ -56,17 +122,9 @@ func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageSe
 		image = *cpf.Image
 	}
 
-	// Set replicas (using MinReplicas as the desired count)
-	replicas := int32(1)
-	if psp.Spec.MinReplicas != nil {
-		replicas = int32(*psp.Spec.MinReplicas)
-	}
+	replicas := pageServerReplicas(psp)
 
-	// Build pod labels
-	labels := map[string]string{
-		"app":       "pageserver",
-		"component": "pageserver-statefulset",
-	}
+	labels := podLabels(ps)
 
 	container := corev1.Container{
 		Name:            "pageserver",
@@ -76,21 +134,18 @@ func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageSe
 		VolumeMounts:    psp.Spec.VolumeMounts,
 	}
 
-	// Add storage volume mount if storage is specified
-	if psp.Spec.Storage != nil {
-		if psp.Spec.Storage.EmptyDir == nil && psp.Spec.Storage.Ephemeral == nil {
-			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
-				Name:      "data",
-				MountPath: "/data",
-			})
-		}
-	}
-
-	// Add configMap volume mount for pageserver.toml
-	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
-		Name:      "config",
-		MountPath: "/data/.neon",
-	})
+	// The workdir holds the config and identity written by the init container
+	container.VolumeMounts = append(container.VolumeMounts,
+		corev1.VolumeMount{
+			Name:      "data",
+			MountPath: dataPath,
+		},
+		corev1.VolumeMount{
+			Name:      runtimeConfigVolumeName,
+			MountPath: runtimeConfigPath,
+			ReadOnly:  true,
+		},
+	)
 
 	// Add TLS secret volume mount
 	if ps.Spec.TLSSecretRef != nil {
@@ -101,11 +156,20 @@ func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageSe
 		})
 	}
 
+	// Add the CA certificate volume mount
+	if controlplane.GetEnableTLS() {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      caCertVolumeName,
+			MountPath: caCertDir,
+			ReadOnly:  true,
+		})
+	}
+
 	// Add JWT public key secret volume mount
 	if ps.Spec.JwtPublicKeySecretRef != nil {
 		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
 			Name:      "jwt-public-key",
-			MountPath: "/etc/pageserver/certs",
+			MountPath: PublicKeyPath,
 			ReadOnly:  true,
 		})
 	}
@@ -182,40 +246,73 @@ func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageSe
 		}
 	}
 
-	// Init container to generate identity.toml with pod name as id
+	// Init container to write identity.toml with the node ID handed out by the control plane,
+	// and to render pageserver.toml with the control plane token
 	initContainer := corev1.Container{
 		Name:  "identity-generator",
 		Image: image,
 		Command: []string{
 			"sh",
 			"-c",
-			"echo \"id=$(hostname)\" > /data/.neon/identity.toml",
+			identityScript,
 		},
 		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "data",
+				MountPath: dataPath,
+			},
 			{
 				Name:      "config",
-				MountPath: "/data/.neon",
+				MountPath: configPath,
+				ReadOnly:  true,
+			},
+			{
+				Name:      "node-ids",
+				MountPath: nodeIDsPath,
+				ReadOnly:  true,
+			},
+			{
+				Name:      runtimeConfigVolumeName,
+				MountPath: runtimeConfigPath,
 			},
 		},
 	}
 
+	// Only the init container reads the control plane token
+	if controlplane.GetEnableJWT() {
+		initContainer.VolumeMounts = append(initContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      tokenVolumeName,
+			MountPath: tokenDir,
+			ReadOnly:  true,
+		})
+	}
+
 	podTemplateSpec := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels: labels,
+			Labels:      labels,
+			Annotations: podAnnotations,
 		},
 		Spec: corev1.PodSpec{
 			InitContainers:   []corev1.Container{initContainer},
 			Containers:       []corev1.Container{container},
 			ImagePullSecrets: cpf.ImagePullSecrets,
 			NodeSelector:     cpf.NodeSelector,
-			Affinity:         cpf.Affinity,
+			Affinity:         podAntiAffinity(psp, labels),
 			SecurityContext:  cpf.SecurityContext,
 			Volumes:          psp.Spec.Volumes,
 		},
 	}
 
-	// Add storage volumes if specified
-	if psp.Spec.Storage != nil {
+	// Add storage volumes if specified, the workdir is not persisted without storage.
+	// Dev mode always runs on an emptyDir.
+	if psp.Spec.Storage == nil || isDevMode(psp) {
+		podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	} else {
 		if psp.Spec.Storage.EmptyDir != nil {
 			podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
 				Name: "data",
@@ -245,6 +342,18 @@ func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageSe
 		},
 	})
 
+	// Add configMap volume for the node IDs of the pods
+	podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
+		Name: "node-ids",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: NodeIDsConfigMapName(psName),
+				},
+			},
+		},
+	})
+
 	// Add TLS secret volume if TLS is enabled and secret is referenced
 	if ps.Spec.TLSSecretRef != nil {
 		podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
@@ -257,6 +366,42 @@ func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageSe
 		})
 	}
 
+	// Add the CA certificate volume if TLS is enabled, copied into the namespace by the NeonCluster
+	if controlplane.GetEnableTLS() {
+		optional := true
+		podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
+			Name: caCertVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: controlplane.CACertSecretName,
+					Optional:   &optional,
+				},
+			},
+		})
+	}
+
+	// Add the in-memory volume of the rendered pageserver.toml
+	podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
+		Name: runtimeConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium: corev1.StorageMediumMemory,
+			},
+		},
+	})
+
+	// Add the control plane token secret volume, the token is never written to the workdir
+	if controlplane.GetEnableJWT() {
+		podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
+			Name: tokenVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: controlplane.TokenSecretName(psName),
+				},
+			},
+		})
+	}
+
 	// Add JWT public key secret volume if JWT is enabled and secret is referenced
 	if ps.Spec.JwtPublicKeySecretRef != nil {
 		podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
@@ -298,7 +443,7 @@ func makePageServerStatefulSetSpec(ps *v1alpha1.PageServer, psp *v1alpha1.PageSe
 	}
 
 	// Add VolumeClaimTemplates if persistent storage is configured
-	if psp.Spec.Storage != nil && psp.Spec.Storage.EmptyDir == nil && psp.Spec.Storage.Ephemeral == nil {
+	if psp.Spec.Storage != nil && psp.Spec.Storage.EmptyDir == nil && psp.Spec.Storage.Ephemeral == nil && !isDevMode(psp) {
 		pvc := psp.Spec.Storage.VolumeClaimTemplate
 		if pvc.EmbeddedObjectMetadata.Name == "" {
 			pvc.EmbeddedObjectMetadata.Name = "data"
@@ -326,14 +471,14 @@ func makePageServerHeadlessService(ps *v1alpha1.PageServer) *corev1.Service {
 			Name:      ps.GetName(),
 			Namespace: ps.Namespace,
 			Labels: map[string]string{
-				"app":       "pageserver",
+				"app":       ps.GetName(),
 				"component": "pageserver-service",
 			},
 		},
 		Spec: corev1.ServiceSpec{
 			ClusterIP: "None", // Headless service
 			Selector: map[string]string{
-				"app": "pageserver",
+				"app": ps.GetName(),
 			},
 			Ports: []corev1.ServicePort{
 				{
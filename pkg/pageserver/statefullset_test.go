@@ -0,0 +1,111 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pageserver
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runIdentityScript runs identityScript with its paths rooted in a temporary directory
+func runIdentityScript(t *testing.T, token string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("failed to get hostname: %v", err)
+	}
+
+	files := map[string]string{
+		configPath + "/pageserver.toml": "control_plane_api = 'http://control-plane/upcall/v1/'\n",
+		nodeIDsPath + "/" + hostname:    "7",
+	}
+	if token != "" {
+		files[TokenPath] = token
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(root+path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(root+path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(root+runtimeConfigPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	script := strings.NewReplacer(
+		configPath, root+configPath,
+		nodeIDsPath, root+nodeIDsPath,
+		dataPath, root+dataPath,
+		tokenDir, root+tokenDir,
+		runtimeConfigPath, root+runtimeConfigPath,
+	).Replace(identityScript)
+	if out, err := exec.Command("sh", "-c", script).CombinedOutput(); err != nil {
+		t.Fatalf("identity script failed: %v: %s", err, out)
+	}
+	return root
+}
+
+func TestIdentityScriptRendersToken(t *testing.T) {
+	root := runIdentityScript(t, "header.payload.signature")
+
+	config, err := os.ReadFile(root + workDir + "/pageserver.toml")
+	if err != nil {
+		t.Fatalf("failed to read pageserver.toml from the workdir: %v", err)
+	}
+	if !strings.Contains(string(config), "control_plane_api_token = 'header.payload.signature'\n") {
+		t.Errorf("pageserver.toml does not hold the token:\n%s", config)
+	}
+	if !strings.HasPrefix(string(config), "control_plane_api = ") {
+		t.Errorf("pageserver.toml does not hold the ConfigMap settings:\n%s", config)
+	}
+
+	// The data volume only links to the in-memory copy
+	target, err := os.Readlink(root + workDir + "/pageserver.toml")
+	if err != nil {
+		t.Fatalf("pageserver.toml in the workdir is not a link: %v", err)
+	}
+	if target != root+runtimeConfigPath+"/pageserver.toml" {
+		t.Errorf("pageserver.toml links to %s, want the runtime config", target)
+	}
+
+	identity, err := os.ReadFile(root + workDir + "/identity.toml")
+	if err != nil {
+		t.Fatalf("failed to read identity.toml: %v", err)
+	}
+	if string(identity) != "id=7\n" {
+		t.Errorf("identity.toml = %q, want %q", identity, "id=7\n")
+	}
+}
+
+func TestIdentityScriptWithoutToken(t *testing.T) {
+	root := runIdentityScript(t, "")
+
+	config, err := os.ReadFile(root + workDir + "/pageserver.toml")
+	if err != nil {
+		t.Fatalf("failed to read pageserver.toml from the workdir: %v", err)
+	}
+	if strings.Contains(string(config), "control_plane_api_token") {
+		t.Errorf("pageserver.toml holds a token without JWT:\n%s", config)
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Merge deep merges patch, the JSON encoding of a partial profile spec, on top of base and decodes
+// the result into out. Objects are merged key by key, any other value replaces the base one and
+// null removes it, as in a JSON merge patch. Fields unknown to out are rejected.
+func Merge(base interface{}, patch []byte, out interface{}) error {
+	merged, err := toMap(base)
+	if err != nil {
+		return err
+	}
+
+	if len(bytes.TrimSpace(patch)) > 0 {
+		overrides := map[string]interface{}{}
+		if err := json.Unmarshal(patch, &overrides); err != nil {
+			return fmt.Errorf("profile override is not a JSON object: %w", err)
+		}
+		merged = mergeObjects(merged, overrides)
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to encode merged profile spec: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("invalid merged profile spec: %w", err)
+	}
+
+	return nil
+}
+
+// mergeObjects merges patch into base and returns base
+func mergeObjects(base, patch map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = map[string]interface{}{}
+	}
+
+	for key, value := range patch {
+		if value == nil {
+			delete(base, key)
+			continue
+		}
+
+		patchObject, ok := value.(map[string]interface{})
+		if !ok {
+			base[key] = value
+			continue
+		}
+
+		// A patch object replacing a non-object value is merged into nothing, which drops its nulls
+		baseObject, _ := base[key].(map[string]interface{})
+		base[key] = mergeObjects(baseObject, patchObject)
+	}
+
+	return base
+}
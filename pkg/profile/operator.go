@@ -0,0 +1,114 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+// Operator resolves the inheritance chain of PageServerProfile, SafeKeeperProfile and StorageBrokerProfile
+// resources into their status, and admits the components using a profile to its changes when it has a
+// rollout policy. Components resolve their profile themselves, the resolved spec in status is informative.
+type Operator struct {
+	nclient client.Client
+	scheme  *runtime.Scheme
+	logger  *slog.Logger
+}
+
+// New creates a new profile Operator.
+func New(nclient client.Client, scheme *runtime.Scheme, logger *slog.Logger) *Operator {
+	return &Operator{
+		logger:  logger.With("component", controllerName),
+		nclient: nclient,
+		scheme:  scheme,
+	}
+}
+
+// sync resolves a profile and records the result in its status
+func (o *Operator) sync(ctx context.Context, k kind, name, namespace string) error {
+	obj := k.newObject()
+	if err := o.nclient.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	logger := o.logger.With("kind", k.name, "key", key)
+
+	previous := obj.DeepCopyObject()
+	status := k.status(obj)
+	cond := metav1.Condition{
+		Type:               v1alpha1.ProfileConditionResolved,
+		ObservedGeneration: obj.GetGeneration(),
+	}
+
+	resolved, chain, err := resolve(ctx, o.nclient, k, obj)
+	switch {
+	case err == nil:
+		k.setResolved(obj, resolved)
+		status.Chain = chain
+		if err := o.updateRollout(ctx, k, obj, resolved); err != nil {
+			return fmt.Errorf("failed to update %s %s rollout: %w", k.name, key, err)
+		}
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Resolved"
+		cond.Message = fmt.Sprintf("resolved from %s", strings.Join(chain, " -> "))
+	case errors.Is(err, ErrBaseProfileNotFound):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "BaseProfileNotFound"
+		cond.Message = err.Error()
+	case errors.Is(err, ErrCycle):
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "CycleDetected"
+		cond.Message = err.Error()
+	default:
+		// The chain could not be read, keep the last resolved spec
+		return fmt.Errorf("failed to resolve %s %s: %w", k.name, key, err)
+	}
+
+	status.ObservedGeneration = obj.GetGeneration()
+	meta.SetStatusCondition(&status.Conditions, cond)
+
+	if equality.Semantic.DeepEqual(previous, obj) {
+		return nil
+	}
+
+	if err := o.nclient.Status().Update(ctx, obj); err != nil {
+		return fmt.Errorf("failed to update %s %s status: %w", k.name, key, err)
+	}
+	logger.Info("Resolved profile", "resolved", cond.Status, "reason", cond.Reason)
+
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const controllerName = "profile-controller"
+
+// Profiles are created with a dry run to read the defaults of their kind
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=pageserverprofiles,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=pageserverprofiles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=safekeeperprofiles,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=safekeeperprofiles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=storagebrokerprofiles,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=storagebrokerprofiles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=pageservers;safekeepers;storagebrokers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=neonclusters,verbs=get;list;watch
+
+// SetupWithManager sets up a controller per profile kind with the Manager.
+func (r *Operator) SetupWithManager(mgr ctrl.Manager) error {
+	for _, k := range kinds {
+		err := ctrl.NewControllerManagedBy(mgr).
+			For(k.newObject()).
+			Watches(
+				k.newObject(),
+				handler.EnqueueRequestsFromMapFunc(r.mapBaseProfileToProfiles(k)),
+			).
+			// The rollout progresses with the status of the components using the profile
+			Watches(
+				k.newComponent(),
+				handler.EnqueueRequestsFromMapFunc(r.mapComponentToProfile(k)),
+			).
+			Named(k.controllerName).
+			Complete(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+				if err := r.sync(ctx, k, req.Name, req.Namespace); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{}, nil
+			}))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapBaseProfileToProfiles returns a map function from a profile change to the profiles inheriting from it,
+// directly or through their resolved chain.
+func (r *Operator) mapBaseProfileToProfiles(k kind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		key := profileKey(obj.GetNamespace(), obj.GetName())
+
+		list := k.newList()
+		if err := r.nclient.List(ctx, list); err != nil {
+			r.logger.Error("failed to list profiles", "kind", k.name, "error", err)
+			return []reconcile.Request{}
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			r.logger.Error("failed to extract profiles", "kind", k.name, "error", err)
+			return []reconcile.Request{}
+		}
+
+		requests := make([]reconcile.Request, 0)
+		for _, item := range items {
+			profile := item.(client.Object)
+			if profileKey(profile.GetNamespace(), profile.GetName()) == key {
+				continue
+			}
+
+			inherits := slices.Contains(k.status(profile).Chain, key)
+			if ref := k.baseRef(profile); ref != nil && ref.Name == obj.GetName() &&
+				(ref.Namespace == "" && profile.GetNamespace() == obj.GetNamespace() || ref.Namespace == obj.GetNamespace()) {
+				inherits = true
+			}
+
+			if inherits {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      profile.GetName(),
+						Namespace: profile.GetNamespace(),
+					},
+				})
+			}
+		}
+
+		return requests
+	}
+}
+
+// mapComponentToProfile returns a map function from a component change to the profile it uses,
+// and to the profile it is generated from when the NeonCluster of the component generated it
+func (r *Operator) mapComponentToProfile(k kind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		ref := k.componentProfileRef(obj)
+		if ref == nil {
+			return []reconcile.Request{}
+		}
+
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = obj.GetNamespace()
+		}
+		requests := []reconcile.Request{{
+			NamespacedName: types.NamespacedName{
+				Name:      ref.Name,
+				Namespace: namespace,
+			},
+		}}
+
+		profile := k.newObject()
+		if err := r.nclient.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, profile); err != nil {
+			return requests
+		}
+		if source := profile.GetAnnotations()[SourceProfileAnnotationKey]; source != "" {
+			sourceNamespace, sourceName, _ := strings.Cut(source, "/")
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      sourceName,
+					Namespace: sourceNamespace,
+				},
+			})
+		}
+		return requests
+	}
+}
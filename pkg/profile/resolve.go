@@ -0,0 +1,418 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+var (
+	// ErrBaseProfileNotFound is returned when a profile of the inheritance chain does not exist
+	ErrBaseProfileNotFound = errors.New("base profile not found")
+	// ErrCycle is returned when the inheritance chain of a profile loops
+	ErrCycle = errors.New("base profile cycle")
+)
+
+// kind describes how the resolver reads a profile kind
+type kind struct {
+	name           string
+	controllerName string
+	newObject      func() client.Object
+	newList        func() client.ObjectList
+	spec           func(client.Object) interface{}
+	baseRef        func(client.Object) *corev1.ObjectReference
+	status         func(client.Object) *v1alpha1.ProfileStatus
+	setResolved    func(obj, resolved client.Object)
+	rollout        func(client.Object) *v1alpha1.ProfileRolloutSpec
+
+	// Components using the profile kind
+	newComponent        func() client.Object
+	newComponentList    func() client.ObjectList
+	componentProfileRef func(client.Object) *corev1.ObjectReference
+	componentStatus     func(client.Object) (*v1alpha1.WorkloadStatus, []metav1.Condition)
+}
+
+var pageServerProfiles = kind{
+	name:           v1alpha1.PageServerProfileKind,
+	controllerName: "pageserverprofile",
+	newObject:      func() client.Object { return &v1alpha1.PageServerProfile{} },
+	newList:        func() client.ObjectList { return &v1alpha1.PageServerProfileList{} },
+	spec:           func(obj client.Object) interface{} { return &obj.(*v1alpha1.PageServerProfile).Spec },
+	baseRef: func(obj client.Object) *corev1.ObjectReference {
+		return obj.(*v1alpha1.PageServerProfile).Spec.BaseProfileRef
+	},
+	status: func(obj client.Object) *v1alpha1.ProfileStatus {
+		return &obj.(*v1alpha1.PageServerProfile).Status.ProfileStatus
+	},
+	setResolved: func(obj, resolved client.Object) {
+		obj.(*v1alpha1.PageServerProfile).Status.Resolved = &resolved.(*v1alpha1.PageServerProfile).Spec
+	},
+	rollout: func(obj client.Object) *v1alpha1.ProfileRolloutSpec {
+		return obj.(*v1alpha1.PageServerProfile).Spec.Rollout
+	},
+	newComponent:     func() client.Object { return &v1alpha1.PageServer{} },
+	newComponentList: func() client.ObjectList { return &v1alpha1.PageServerList{} },
+	componentProfileRef: func(obj client.Object) *corev1.ObjectReference {
+		return obj.(*v1alpha1.PageServer).Spec.ProfileRef
+	},
+	componentStatus: func(obj client.Object) (*v1alpha1.WorkloadStatus, []metav1.Condition) {
+		c := obj.(*v1alpha1.PageServer)
+		return &c.Status.WorkloadStatus, c.Status.Conditions
+	},
+}
+
+var safeKeeperProfiles = kind{
+	name:           v1alpha1.SafeKeeperProfileKind,
+	controllerName: "safekeeperprofile",
+	newObject:      func() client.Object { return &v1alpha1.SafeKeeperProfile{} },
+	newList:        func() client.ObjectList { return &v1alpha1.SafeKeeperProfileList{} },
+	spec:           func(obj client.Object) interface{} { return &obj.(*v1alpha1.SafeKeeperProfile).Spec },
+	baseRef: func(obj client.Object) *corev1.ObjectReference {
+		return obj.(*v1alpha1.SafeKeeperProfile).Spec.BaseProfileRef
+	},
+	status: func(obj client.Object) *v1alpha1.ProfileStatus {
+		return &obj.(*v1alpha1.SafeKeeperProfile).Status.ProfileStatus
+	},
+	setResolved: func(obj, resolved client.Object) {
+		obj.(*v1alpha1.SafeKeeperProfile).Status.Resolved = &resolved.(*v1alpha1.SafeKeeperProfile).Spec
+	},
+	rollout: func(obj client.Object) *v1alpha1.ProfileRolloutSpec {
+		return obj.(*v1alpha1.SafeKeeperProfile).Spec.Rollout
+	},
+	newComponent:     func() client.Object { return &v1alpha1.SafeKeeper{} },
+	newComponentList: func() client.ObjectList { return &v1alpha1.SafeKeeperList{} },
+	componentProfileRef: func(obj client.Object) *corev1.ObjectReference {
+		return obj.(*v1alpha1.SafeKeeper).Spec.ProfileRef
+	},
+	componentStatus: func(obj client.Object) (*v1alpha1.WorkloadStatus, []metav1.Condition) {
+		c := obj.(*v1alpha1.SafeKeeper)
+		return &c.Status.WorkloadStatus, c.Status.Conditions
+	},
+}
+
+var storageBrokerProfiles = kind{
+	name:           v1alpha1.StorageBrokerProfileKind,
+	controllerName: "storagebrokerprofile",
+	newObject:      func() client.Object { return &v1alpha1.StorageBrokerProfile{} },
+	newList:        func() client.ObjectList { return &v1alpha1.StorageBrokerProfileList{} },
+	spec:           func(obj client.Object) interface{} { return &obj.(*v1alpha1.StorageBrokerProfile).Spec },
+	baseRef: func(obj client.Object) *corev1.ObjectReference {
+		return obj.(*v1alpha1.StorageBrokerProfile).Spec.BaseProfileRef
+	},
+	status: func(obj client.Object) *v1alpha1.ProfileStatus {
+		return &obj.(*v1alpha1.StorageBrokerProfile).Status.ProfileStatus
+	},
+	setResolved: func(obj, resolved client.Object) {
+		obj.(*v1alpha1.StorageBrokerProfile).Status.Resolved = &resolved.(*v1alpha1.StorageBrokerProfile).Spec
+	},
+	rollout: func(obj client.Object) *v1alpha1.ProfileRolloutSpec {
+		return obj.(*v1alpha1.StorageBrokerProfile).Spec.Rollout
+	},
+	newComponent:     func() client.Object { return &v1alpha1.StorageBroker{} },
+	newComponentList: func() client.ObjectList { return &v1alpha1.StorageBrokerList{} },
+	componentProfileRef: func(obj client.Object) *corev1.ObjectReference {
+		return obj.(*v1alpha1.StorageBroker).Spec.ProfileRef
+	},
+	componentStatus: func(obj client.Object) (*v1alpha1.WorkloadStatus, []metav1.Condition) {
+		c := obj.(*v1alpha1.StorageBroker)
+		return &c.Status.WorkloadStatus, c.Status.Conditions
+	},
+}
+
+// kinds are the profile kinds supporting inheritance
+var kinds = []kind{pageServerProfiles, safeKeeperProfiles, storageBrokerProfiles}
+
+// defaultSpecs caches the spec of an empty profile after API server defaulting, by kind
+var defaultSpecs sync.Map
+
+// ResolvePageServerProfile returns a copy of the PageServerProfile with its inheritance chain merged into its spec
+func ResolvePageServerProfile(ctx context.Context, c client.Client, psp *v1alpha1.PageServerProfile) (*v1alpha1.PageServerProfile, error) {
+	resolved, _, err := resolve(ctx, c, pageServerProfiles, psp)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(*v1alpha1.PageServerProfile), nil
+}
+
+// ResolveSafeKeeperProfile returns a copy of the SafeKeeperProfile with its inheritance chain merged into its spec
+func ResolveSafeKeeperProfile(ctx context.Context, c client.Client, skp *v1alpha1.SafeKeeperProfile) (*v1alpha1.SafeKeeperProfile, error) {
+	resolved, _, err := resolve(ctx, c, safeKeeperProfiles, skp)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(*v1alpha1.SafeKeeperProfile), nil
+}
+
+// ResolveStorageBrokerProfile returns a copy of the StorageBrokerProfile with its inheritance chain merged into its spec
+func ResolveStorageBrokerProfile(ctx context.Context, c client.Client, sbp *v1alpha1.StorageBrokerProfile) (*v1alpha1.StorageBrokerProfile, error) {
+	resolved, _, err := resolve(ctx, c, storageBrokerProfiles, sbp)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(*v1alpha1.StorageBrokerProfile), nil
+}
+
+// resolve walks the base profiles of obj and returns a copy of obj whose spec is the chain deep merged,
+// along with the chain as namespace/name from the root base profile to obj.
+// The root profile is taken as is, every other profile only contributes the fields set in it,
+// so that the defaults the API server fills in do not reset what its base profile sets.
+func resolve(ctx context.Context, c client.Client, k kind, obj client.Object) (client.Object, []string, error) {
+	chain := []client.Object{obj}
+	names := []string{profileKey(obj.GetNamespace(), obj.GetName())}
+
+	for current := obj; k.baseRef(current) != nil; {
+		ref := k.baseRef(current)
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = current.GetNamespace()
+		}
+
+		key := profileKey(namespace, ref.Name)
+		if slices.Contains(names, key) {
+			return nil, nil, fmt.Errorf("%w: %s -> %s", ErrCycle, strings.Join(names, " -> "), key)
+		}
+
+		base := k.newObject()
+		if err := c.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: namespace}, base); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil, fmt.Errorf("%w: %s %s", ErrBaseProfileNotFound, k.name, key)
+			}
+			return nil, nil, fmt.Errorf("failed to get %s %s: %w", k.name, key, err)
+		}
+
+		chain = append(chain, base)
+		names = append(names, key)
+		current = base
+	}
+
+	// Root first
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+		names[i], names[j] = names[j], names[i]
+	}
+
+	resolved := obj.DeepCopyObject().(client.Object)
+	if len(chain) == 1 {
+		return resolved, names, nil
+	}
+
+	merged, err := toMap(k.spec(chain[0]))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defaults, err := defaultSpec(ctx, c, k, obj.GetNamespace())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, profile := range chain[1:] {
+		spec, err := toMap(k.spec(profile))
+		if err != nil {
+			return nil, nil, err
+		}
+		explicit, err := appliedSpecFields(profile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %s: %w", k.name, profileKey(profile.GetNamespace(), profile.GetName()), err)
+		}
+		merged = mergeObjects(merged, setFields(spec, defaults, explicit))
+	}
+	delete(merged, "baseProfileRef")
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode resolved %s spec: %w", k.name, err)
+	}
+
+	spec := k.spec(resolved)
+	reflect.ValueOf(spec).Elem().SetZero()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		return nil, nil, fmt.Errorf("invalid resolved %s spec: %w", k.name, err)
+	}
+
+	return resolved, names, nil
+}
+
+// defaultSpec returns the spec of an empty profile of the kind after API server defaulting.
+// It is computed once with a dry run create.
+func defaultSpec(ctx context.Context, c client.Client, k kind, namespace string) (map[string]interface{}, error) {
+	if defaults, ok := defaultSpecs.Load(k.name); ok {
+		return defaults.(map[string]interface{}), nil
+	}
+
+	obj := k.newObject()
+	obj.SetGenerateName("defaults-")
+	obj.SetNamespace(namespace)
+	if err := c.Create(ctx, obj, client.DryRunAll); err != nil {
+		return nil, fmt.Errorf("failed to get %s defaults: %w", k.name, err)
+	}
+
+	defaults, err := toMap(k.spec(obj))
+	if err != nil {
+		return nil, err
+	}
+	defaultSpecs.Store(k.name, defaults)
+
+	return defaults, nil
+}
+
+// fieldSet is a tree of fields set in an object, by JSON name. A field without children is set as a whole.
+type fieldSet map[string]fieldSet
+
+// appliedSpecFields returns the spec fields a profile was applied with, by server-side apply or kubectl apply.
+// Unlike the fields recorded for updates, they do not include the defaults filled in by the API server.
+func appliedSpecFields(obj client.Object) (fieldSet, error) {
+	fields := fieldSet{}
+
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		managed := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &managed); err != nil {
+			return nil, fmt.Errorf("failed to decode managed fields of %s: %w", entry.Manager, err)
+		}
+		if spec, ok := managed["f:spec"].(map[string]interface{}); ok {
+			fields.merge(managedFieldSet(spec))
+		}
+	}
+
+	if lastApplied, ok := obj.GetAnnotations()[corev1.LastAppliedConfigAnnotation]; ok {
+		applied := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(lastApplied), &applied); err != nil {
+			return nil, fmt.Errorf("failed to decode last applied configuration: %w", err)
+		}
+		if spec, ok := applied["spec"].(map[string]interface{}); ok {
+			fields.merge(objectFieldSet(spec))
+		}
+	}
+
+	return fields, nil
+}
+
+// managedFieldSet returns the fields of a managedFields entry, lists are set as a whole
+func managedFieldSet(managed map[string]interface{}) fieldSet {
+	fields := fieldSet{}
+	for key, value := range managed {
+		name, isField := strings.CutPrefix(key, "f:")
+		switch {
+		case key == ".":
+		case !isField:
+			// Keys and values of list items
+			return fieldSet{}
+		default:
+			children, _ := value.(map[string]interface{})
+			fields[name] = managedFieldSet(children)
+		}
+	}
+	return fields
+}
+
+// objectFieldSet returns the fields of a JSON object, any value other than a non-empty object is set as a whole
+func objectFieldSet(object map[string]interface{}) fieldSet {
+	fields := fieldSet{}
+	for key, value := range object {
+		children, _ := value.(map[string]interface{})
+		fields[key] = objectFieldSet(children)
+	}
+	return fields
+}
+
+// merge adds the fields of other, a field set as a whole stays set as a whole
+func (fs fieldSet) merge(other fieldSet) {
+	for key, children := range other {
+		current, ok := fs[key]
+		switch {
+		case !ok:
+			fs[key] = children
+		case len(current) == 0:
+		case len(children) == 0:
+			fs[key] = fieldSet{}
+		default:
+			current.merge(children)
+		}
+	}
+}
+
+// setFields returns the fields set in a profile: the fields of spec it was applied with
+// and the fields whose values differ from defaults. Objects are compared key by key, any other value is kept whole.
+// A field set to its default value with a plain create or update cannot be told apart from an unset one.
+func setFields(spec, defaults map[string]interface{}, explicit fieldSet) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for key, value := range spec {
+		children, applied := explicit[key]
+		if applied && len(children) == 0 {
+			fields[key] = value
+			continue
+		}
+
+		defaultValue, ok := defaults[key]
+		if !ok {
+			fields[key] = value
+			continue
+		}
+
+		valueObject, isObject := value.(map[string]interface{})
+		defaultObject, isDefaultObject := defaultValue.(map[string]interface{})
+		if isObject && isDefaultObject {
+			if set := setFields(valueObject, defaultObject, children); len(set) > 0 {
+				fields[key] = set
+			}
+			continue
+		}
+		if !reflect.DeepEqual(value, defaultValue) {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// toMap returns the JSON object encoding spec
+func toMap(spec interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode profile spec: %w", err)
+	}
+
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode profile spec: %w", err)
+	}
+	return out, nil
+}
+
+// profileKey returns the namespace/name of a profile
+func profileKey(namespace, name string) string {
+	return namespace + "/" + name
+}
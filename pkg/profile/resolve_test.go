@@ -0,0 +1,145 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+func TestSetFields(t *testing.T) {
+	defaults := map[string]interface{}{
+		"mode":     "production",
+		"replicas": float64(1),
+		"durability": map[string]interface{}{
+			"fsync": true,
+		},
+	}
+	// A profile created with every field left to its default
+	spec := map[string]interface{}{
+		"mode":     "production",
+		"replicas": float64(1),
+		"durability": map[string]interface{}{
+			"fsync": true,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		managed     []metav1.ManagedFieldsEntry
+		annotations map[string]string
+		spec        map[string]interface{}
+		want        map[string]interface{}
+	}{
+		{
+			name: "defaults are not set",
+			spec: spec,
+			want: map[string]interface{}{},
+		},
+		{
+			name: "values differing from defaults are set",
+			spec: map[string]interface{}{
+				"mode":     "dev",
+				"replicas": float64(1),
+				"durability": map[string]interface{}{
+					"fsync": false,
+				},
+			},
+			want: map[string]interface{}{
+				"mode": "dev",
+				"durability": map[string]interface{}{
+					"fsync": false,
+				},
+			},
+		},
+		{
+			name: "default value applied server-side is set",
+			managed: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kubectl",
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:mode":{},"f:durability":{"f:fsync":{}}}}`)},
+				},
+				{
+					// Updates own the defaults filled in by the API server
+					Manager:   "kubectl-create",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+				},
+			},
+			spec: spec,
+			want: map[string]interface{}{
+				"mode": "production",
+				"durability": map[string]interface{}{
+					"fsync": true,
+				},
+			},
+		},
+		{
+			name: "default value applied by kubectl is set",
+			annotations: map[string]string{
+				corev1.LastAppliedConfigAnnotation: `{"spec":{"mode":"production"}}`,
+			},
+			spec: spec,
+			want: map[string]interface{}{
+				"mode": "production",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			psp := &v1alpha1.PageServerProfile{}
+			psp.SetManagedFields(tt.managed)
+			psp.SetAnnotations(tt.annotations)
+
+			explicit, err := appliedSpecFields(psp)
+			if err != nil {
+				t.Fatalf("appliedSpecFields() error = %v", err)
+			}
+			if got := setFields(tt.spec, defaults, explicit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFieldsOverrideBase(t *testing.T) {
+	// mode: production applied over a dev base profile, production being the default mode
+	base := map[string]interface{}{"mode": "dev"}
+	defaults := map[string]interface{}{"mode": "production"}
+	child := &v1alpha1.PageServerProfile{}
+	child.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager:   "kubectl",
+		Operation: metav1.ManagedFieldsOperationApply,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:mode":{},"f:baseProfileRef":{"f:name":{}}}}`)},
+	}})
+
+	explicit, err := appliedSpecFields(child)
+	if err != nil {
+		t.Fatalf("appliedSpecFields() error = %v", err)
+	}
+	merged := mergeObjects(base, setFields(map[string]interface{}{"mode": "production"}, defaults, explicit))
+	if merged["mode"] != "production" {
+		t.Errorf("resolved mode = %v, want production", merged["mode"])
+	}
+}
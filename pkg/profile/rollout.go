@@ -0,0 +1,360 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+)
+
+const (
+	// SourceProfileAnnotationKey is the namespace/name of the shared profile a profile generated
+	// for the overrides of a NeonCluster is built from. Generated profiles have no rollout policy,
+	// the rollout of the shared profile admits their components.
+	SourceProfileAnnotationKey = "neon.io/source-profile"
+	// SourceProfileHashAnnotationKey is the hash of the shared profile a generated profile is built from
+	SourceProfileHashAnnotationKey = "neon.io/source-profile-hash"
+)
+
+// Hash returns the hash of the spec of a resolved profile, the rollout policy excluded.
+// Components report it as their profileHash once they applied the profile.
+func Hash(obj client.Object) (string, error) {
+	k, err := kindOf(obj)
+	if err != nil {
+		return "", err
+	}
+
+	spec, err := toMap(k.spec(obj))
+	if err != nil {
+		return "", err
+	}
+	// Changing how a profile rolls out is not a change to roll out
+	delete(spec, "rollout")
+
+	return k8sutils.CreateInputHash(metav1.ObjectMeta{}, spec)
+}
+
+// RolloutAdmitted reports whether a component may apply the resolved profile, whose spec hashes to hash.
+// appliedHash is the hash of the profile the component runs with, empty before its first apply.
+// Components of profiles without a rollout policy, new components and components already running
+// the profile are always admitted, the others once the profile rollout admitted them.
+func RolloutAdmitted(obj client.Object, component client.Object, appliedHash, hash string) bool {
+	k, err := kindOf(obj)
+	if err != nil || k.rollout(obj) == nil || appliedHash == "" || appliedHash == hash {
+		return true
+	}
+
+	rollout := k.status(obj).Rollout
+	return rollout != nil && rollout.Hash == hash &&
+		slices.Contains(rollout.Admitted, profileKey(component.GetNamespace(), component.GetName()))
+}
+
+// kindOf returns the kind of a profile object
+func kindOf(obj client.Object) (kind, error) {
+	switch obj.(type) {
+	case *v1alpha1.PageServerProfile:
+		return pageServerProfiles, nil
+	case *v1alpha1.SafeKeeperProfile:
+		return safeKeeperProfiles, nil
+	case *v1alpha1.StorageBrokerProfile:
+		return storageBrokerProfiles, nil
+	}
+	return kind{}, fmt.Errorf("%T is not a profile", obj)
+}
+
+// rolloutCluster is the state of a cluster using a profile during a rollout
+type rolloutCluster struct {
+	name       string
+	components []string
+	canary     bool
+	admitted   bool
+	// updated is true when every component runs the change
+	updated bool
+	// available is false when a component is rolling out or degraded
+	available bool
+	// degraded is true when a component reports a failure
+	degraded bool
+}
+
+// updateRollout admits the next clusters to the change of the resolved profile into the status of obj.
+// Clusters are admitted while fewer than maxUnavailableClusters are rolling out or unavailable, canary
+// clusters first. Admissions stop while an admitted cluster is degraded, if the policy pauses on failure.
+func (o *Operator) updateRollout(ctx context.Context, k kind, obj, resolved client.Object) error {
+	status := k.status(obj)
+	policy := k.rollout(resolved)
+	if policy == nil {
+		status.Rollout = nil
+		meta.RemoveStatusCondition(&status.Conditions, v1alpha1.ProfileConditionRolloutPaused)
+		return nil
+	}
+
+	hash, err := Hash(resolved)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", k.name, err)
+	}
+
+	rollout := &v1alpha1.ProfileRolloutStatus{Hash: hash}
+	if status.Rollout != nil && status.Rollout.Hash == hash {
+		rollout.Admitted = status.Rollout.Admitted
+	}
+
+	clusters, err := o.rolloutClusters(ctx, k, obj, policy, hash, rollout.Admitted)
+	if err != nil {
+		return err
+	}
+
+	unavailable := int32(0)
+	canariesUpdated := true
+	var failed []string
+	for _, cluster := range clusters {
+		if !cluster.available {
+			unavailable++
+		}
+		if cluster.updated && cluster.available {
+			rollout.UpdatedClusters++
+		}
+		if cluster.canary && !(cluster.updated && cluster.available) {
+			canariesUpdated = false
+		}
+		if cluster.admitted && cluster.degraded {
+			failed = append(failed, cluster.name)
+		}
+	}
+
+	paused := len(failed) > 0 && (policy.PauseOnFailure == nil || *policy.PauseOnFailure)
+	maxUnavailable := max(policy.MaxUnavailableClusters, 1)
+	if !paused {
+		for _, cluster := range clusters {
+			if unavailable >= maxUnavailable {
+				break
+			}
+			if cluster.admitted || cluster.updated || (!cluster.canary && !canariesUpdated) {
+				continue
+			}
+			rollout.Admitted = append(rollout.Admitted, cluster.components...)
+			cluster.admitted = true
+			if cluster.available {
+				unavailable++
+			}
+		}
+	}
+
+	rollout.Clusters = int32(len(clusters))
+	sort.Strings(rollout.Admitted)
+	status.Rollout = rollout
+
+	cond := metav1.Condition{
+		Type:               v1alpha1.ProfileConditionRolloutPaused,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RollingOut",
+		Message:            fmt.Sprintf("%d/%d clusters updated", rollout.UpdatedClusters, rollout.Clusters),
+		ObservedGeneration: obj.GetGeneration(),
+	}
+	if paused {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "ClusterDegraded"
+		cond.Message = fmt.Sprintf("degraded after rolling out the change: %s", strings.Join(failed, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, cond)
+
+	return nil
+}
+
+// rolloutClusters returns the clusters whose components use the profile, canary clusters first, sorted by name.
+// Components not managed by a NeonCluster are clusters of their own.
+func (o *Operator) rolloutClusters(ctx context.Context, k kind, obj client.Object, policy *v1alpha1.ProfileRolloutSpec, hash string, admitted []string) ([]*rolloutCluster, error) {
+	var canarySelector labels.Selector
+	if policy.CanarySelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(policy.CanarySelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid canary selector: %w", err)
+		}
+		canarySelector = selector
+	}
+
+	generated, err := o.generatedProfiles(ctx, k, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	list := k.newComponentList()
+	if err := o.nclient.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list components using %s: %w", k.name, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract components using %s: %w", k.name, err)
+	}
+
+	byName := map[string]*rolloutCluster{}
+	var clusters []*rolloutCluster
+	for _, item := range items {
+		component := item.(client.Object)
+		updated := func(appliedHash string) bool { return appliedHash == hash }
+		if !usesProfile(k, component, obj) {
+			// Components of a generated profile run the change once their profile is built from it
+			profile, ok := generated[componentProfileKey(k, component)]
+			if !ok {
+				continue
+			}
+			updated = func(appliedHash string) bool { return profile.sourceHash == hash && appliedHash == profile.hash }
+		}
+
+		name := profileKey(component.GetNamespace(), component.GetName())
+		clusterName := name
+		clusterLabels := labels.Set(component.GetLabels())
+		if nc := component.GetLabels()["neoncluster"]; nc != "" {
+			clusterName = profileKey(component.GetNamespace(), nc)
+			if canarySelector != nil {
+				clusterLabels, err = o.neonClusterLabels(ctx, component.GetNamespace(), nc)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		cluster, ok := byName[clusterName]
+		if !ok {
+			cluster = &rolloutCluster{
+				name:      clusterName,
+				canary:    canarySelector != nil && canarySelector.Matches(clusterLabels),
+				updated:   true,
+				available: true,
+			}
+			byName[clusterName] = cluster
+			clusters = append(clusters, cluster)
+		}
+
+		workload, conditions := k.componentStatus(component)
+		cluster.components = append(cluster.components, name)
+		if slices.Contains(admitted, name) {
+			cluster.admitted = true
+		}
+		if !updated(workload.ProfileHash) {
+			cluster.updated = false
+		}
+		if !componentAvailable(component, workload, conditions) {
+			cluster.available = false
+		}
+		if cond := meta.FindStatusCondition(conditions, v1alpha1.ComponentConditionDegraded); cond != nil && cond.Status == metav1.ConditionTrue {
+			cluster.degraded = true
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].canary != clusters[j].canary {
+			return clusters[i].canary
+		}
+		return clusters[i].name < clusters[j].name
+	})
+
+	return clusters, nil
+}
+
+// generatedProfile is a profile generated from the profile being rolled out
+type generatedProfile struct {
+	// sourceHash is the hash of the profile it is built from
+	sourceHash string
+	// hash is its own hash, reported by its components once they applied it
+	hash string
+}
+
+// generatedProfiles returns the profiles generated from obj for the overrides of NeonClusters, by namespace/name
+func (o *Operator) generatedProfiles(ctx context.Context, k kind, obj client.Object) (map[string]generatedProfile, error) {
+	list := k.newList()
+	if err := o.nclient.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", k.name, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", k.name, err)
+	}
+
+	source := profileKey(obj.GetNamespace(), obj.GetName())
+	generated := map[string]generatedProfile{}
+	for _, item := range items {
+		profile := item.(client.Object)
+		if profile.GetAnnotations()[SourceProfileAnnotationKey] != source {
+			continue
+		}
+		hash, err := Hash(profile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s %s: %w", k.name, profile.GetName(), err)
+		}
+		generated[profileKey(profile.GetNamespace(), profile.GetName())] = generatedProfile{
+			sourceHash: profile.GetAnnotations()[SourceProfileHashAnnotationKey],
+			hash:       hash,
+		}
+	}
+	return generated, nil
+}
+
+// neonClusterLabels returns the labels of a NeonCluster, matched by the canary selector
+func (o *Operator) neonClusterLabels(ctx context.Context, namespace, name string) (labels.Set, error) {
+	nc := &v1alpha1.NeonCluster{}
+	if err := o.nclient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, nc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return labels.Set{}, nil
+		}
+		return nil, fmt.Errorf("failed to get neoncluster %s/%s: %w", namespace, name, err)
+	}
+	return labels.Set(nc.Labels), nil
+}
+
+// componentAvailable reports whether a component observed its spec, is rolled out and is not degraded.
+// A component holding back the change until it is admitted is available.
+func componentAvailable(component client.Object, workload *v1alpha1.WorkloadStatus, conditions []metav1.Condition) bool {
+	if workload.ObservedGeneration < component.GetGeneration() {
+		return false
+	}
+	if cond := meta.FindStatusCondition(conditions, v1alpha1.ComponentConditionDegraded); cond == nil || cond.Status != metav1.ConditionFalse {
+		return false
+	}
+	cond := meta.FindStatusCondition(conditions, v1alpha1.ComponentConditionProgressing)
+	return cond != nil && (cond.Status == metav1.ConditionFalse || cond.Reason == operator.ReasonProfileRolloutPending)
+}
+
+// usesProfile reports whether a component references the profile, in its own namespace by default
+func usesProfile(k kind, component, obj client.Object) bool {
+	return componentProfileKey(k, component) == profileKey(obj.GetNamespace(), obj.GetName())
+}
+
+// componentProfileKey returns the namespace/name of the profile of a component, empty without one
+func componentProfileKey(k kind, component client.Object) string {
+	ref := k.componentProfileRef(component)
+	if ref == nil {
+		return ""
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = component.GetNamespace()
+	}
+	return profileKey(namespace, ref.Name)
+}
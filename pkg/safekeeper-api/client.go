@@ -0,0 +1,127 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package safekeeperapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+// ErrNotFound is returned when the safekeeper responds with 404 Not Found.
+var ErrNotFound = errors.New("not found")
+
+// Client talks to the HTTP API of a single safekeeper.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// TimelineStatus is the response of GET /v1/tenant/{tenant_id}/timeline/{timeline_id}.
+type TimelineStatus struct {
+	TenantID            string `json:"tenant_id"`
+	TimelineID          string `json:"timeline_id"`
+	CommitLSN           string `json:"commit_lsn"`
+	FlushLSN            string `json:"flush_lsn"`
+	BackupLSN           string `json:"backup_lsn"`
+	RemoteConsistentLSN string `json:"remote_consistent_lsn"`
+}
+
+// NewClient creates a client for the safekeeper HTTP API at baseURL.
+// token is sent as a bearer token when it is not empty.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: requestTimeout,
+		},
+	}
+}
+
+// TimelineStatus returns the WAL positions of a timeline, or ErrNotFound if the safekeeper does not host it.
+func (c *Client) TimelineStatus(ctx context.Context, tenantID, timelineID string) (*TimelineStatus, error) {
+	status := &TimelineStatus{}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/tenant/%s/timeline/%s", tenantID, timelineID), status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// ParseLSN parses an LSN in the Postgres "hi/lo" hexadecimal notation.
+func ParseLSN(lsn string) (uint64, error) {
+	hi, lo, ok := strings.Cut(lsn, "/")
+	if !ok {
+		return 0, fmt.Errorf("invalid LSN %q", lsn)
+	}
+	h, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q: %w", lsn, err)
+	}
+	l, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid LSN %q: %w", lsn, err)
+	}
+	return h<<32 | l, nil
+}
+
+// do sends a request to the safekeeper and decodes the JSON response into out when it is not nil
+func (c *Client) do(ctx context.Context, method, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", method, path, ErrNotFound)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, string(msg))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+
+	return nil
+}
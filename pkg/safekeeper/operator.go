@@ -18,10 +18,12 @@ package safekeeper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -30,7 +32,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
 	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+	"github.com/stateless-pg/stateless-pg/pkg/profile"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -89,6 +95,11 @@ func (o *Operator) isStorageBrokerTLSEnabled(ctx context.Context, sk *v1alpha1.S
 		return false, fmt.Errorf("failed to get storagebroker profile: %w", err)
 	}
 
+	sbProf, err := profile.ResolveStorageBrokerProfile(ctx, o.nclient, sbProf)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve storagebroker profile: %w", err)
+	}
+
 	return sbProf.Spec.EnableTLS, nil
 }
 
@@ -113,73 +124,189 @@ func (o *Operator) sync(ctx context.Context, name, namespace string) error {
 	logger := o.logger.With("key", key)
 	logger.Info("syncing safekeeper")
 
-	profile := &v1alpha1.SafeKeeperProfile{}
+	ss, profileHash, syncErr := o.reconcile(ctx, sk)
+
+	// Status is written even when the reconcile failed, Degraded reports the failure
+	if err := o.updateStatus(ctx, sk, ss, profileHash, syncErr); err != nil {
+		if syncErr != nil {
+			logger.Error("failed to update safekeeper status", "error", err)
+			return syncErr
+		}
+		return err
+	}
+
+	if errors.Is(syncErr, operator.ErrProfileRolloutPending) {
+		// Requeued by the profile watch once the rollout admits it
+		return nil
+	}
+	return syncErr
+}
+
+// reconcile creates or updates the children of the SafeKeeper.
+// It returns the StatefulSet, nil while it is being recreated, and the hash of the applied profile.
+func (o *Operator) reconcile(ctx context.Context, sk *v1alpha1.SafeKeeper) (*appsv1.StatefulSet, string, error) {
+	skp := &v1alpha1.SafeKeeperProfile{}
 	if err := o.nclient.Get(ctx, client.ObjectKey{
 		Name:      sk.Spec.ProfileRef.Name,
 		Namespace: sk.Spec.ProfileRef.Namespace,
-	}, profile); err != nil {
-		return fmt.Errorf("failed to get safekeeper profile : %w", err)
+	}, skp); err != nil {
+		return nil, "", fmt.Errorf("failed to get safekeeper profile : %w", err)
 	}
 
-	profile = profile.DeepCopy()
+	// Merge the base profiles the profile inherits from
+	skp, err := profile.ResolveSafeKeeperProfile(ctx, o.nclient, skp)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve safekeeper profile: %w", err)
+	}
+
+	profileHash, err := profile.Hash(skp)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create input hash for safekeeper profile: %w", err)
+	}
+
+	// A change of a profile with a rollout policy waits until the rollout admits this SafeKeeper
+	if !profile.RolloutAdmitted(skp, sk, sk.Status.ProfileHash, profileHash) {
+		ss, err := o.kclient.AppsV1().StatefulSets(sk.GetNamespace()).Get(ctx, sk.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, sk.Status.ProfileHash, fmt.Errorf("failed to get safekeeper statefulset: %w", err)
+		}
+		return ss, sk.Status.ProfileHash, operator.ErrProfileRolloutPending
+	}
 
 	// Check if TLS is enabled in StorageBrokerProfile
 	storageBrokerTLSEnabled, err := o.isStorageBrokerTLSEnabled(ctx, sk)
 	if err != nil {
-		return fmt.Errorf("failed to check storagebroker tls status: %w", err)
+		return nil, profileHash, fmt.Errorf("failed to check storagebroker tls status: %w", err)
 	}
 
 	if err := o.updateHeadlessService(ctx, sk); err != nil {
-		return fmt.Errorf("failed to reconcile safekeeper headless service: %w", err)
+		return nil, profileHash, fmt.Errorf("failed to reconcile safekeeper headless service: %w", err)
+	}
+
+	// The token authenticates the safekeeper to its peers, the token file is read again when it is refreshed
+	_, err = controlplane.UpdateTokenSecret(ctx, o.kclient, sk,
+		fmt.Sprintf("safekeeper/%s/%s", sk.GetNamespace(), sk.GetName()), controlplane.ScopeSafekeeperData,
+		map[string]string{
+			"app":       sk.GetName(),
+			"component": "safekeeper-token",
+		})
+	if err != nil {
+		return nil, profileHash, fmt.Errorf("failed to reconcile safekeeper token secret: %w", err)
+	}
+
+	// The public keys are read on startup, their changes roll the pods
+	podAnnotations := map[string]string{}
+	if sk.Spec.JwtPublicKeySecretRef != nil {
+		keyIDs, err := controlplane.TrustedKeyIDs(ctx, o.kclient, sk.Namespace, sk.Spec.JwtPublicKeySecretRef)
+		if err != nil {
+			return nil, profileHash, err
+		}
+		podAnnotations[controlplane.JWTKeyIDsAnnotationKey] = keyIDs
+	}
+
+	ss, err := o.updateStatefulSet(ctx, sk, skp, storageBrokerTLSEnabled, podAnnotations)
+	if err != nil {
+		return nil, profileHash, fmt.Errorf("failed to reconcile safekeeper statefulset: %w", err)
 	}
 
-	if err := o.updateStatefulSet(ctx, sk, profile, storageBrokerTLSEnabled); err != nil {
-		return fmt.Errorf("failed to reconcile safekeeper statefulset: %w", err)
+	sk.Status.Members = makeSafeKeeperMembers(sk, safeKeeperReplicas(skp))
+
+	return ss, profileHash, nil
+}
+
+// updateStatus persists the workload status and conditions of the SafeKeeper
+func (o *Operator) updateStatus(ctx context.Context, sk *v1alpha1.SafeKeeper, ss *appsv1.StatefulSet, profileHash string, syncErr error) error {
+	if profileHash == "" {
+		// The profile could not be read, keep reporting the last applied one
+		profileHash = sk.Status.ProfileHash
 	}
 
+	sk.Status.WorkloadStatus = operator.StatefulSetStatus(ss, sk.Generation, profileHash)
+	operator.SetWorkloadConditions(&sk.Status.Conditions, sk.Generation, &sk.Status.WorkloadStatus,
+		ss != nil, operator.StatefulSetRolledOut(ss), syncErr)
+
+	if err := o.nclient.Status().Update(ctx, sk); err != nil {
+		return fmt.Errorf("failed to update safekeeper status: %w", err)
+	}
 	return nil
 }
 
-func (o *Operator) updateStatefulSet(ctx context.Context, sk *v1alpha1.SafeKeeper, profile *v1alpha1.SafeKeeperProfile, storageBrokerTLSEnabled bool) error {
+// makeSafeKeeperMembers returns the quorum members of the SafeKeeper, one per StatefulSet ordinal
+func makeSafeKeeperMembers(sk *v1alpha1.SafeKeeper, replicas int32) []v1alpha1.SafeKeeperMember {
+	members := make([]v1alpha1.SafeKeeperMember, 0, replicas)
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		pod := fmt.Sprintf("%s-%d", sk.Name, ordinal)
+		host := fmt.Sprintf("%s.%s.%s.svc.cluster.local", pod, sk.Name, sk.Namespace)
+		members = append(members, v1alpha1.SafeKeeperMember{
+			Pod:      pod,
+			NodeID:   SafeKeeperNodeID(ordinal),
+			PgAddr:   fmt.Sprintf("%s:%d", host, PgPort),
+			HTTPAddr: fmt.Sprintf("%s:%d", host, HTTPPort),
+		})
+	}
+	return members
+}
+
+// updateStatefulSet creates or updates the SafeKeeper StatefulSet.
+// podAnnotations are set on the pod template, their changes roll the pods.
+func (o *Operator) updateStatefulSet(ctx context.Context, sk *v1alpha1.SafeKeeper, profile *v1alpha1.SafeKeeperProfile, storageBrokerTLSEnabled bool, podAnnotations map[string]string) (*appsv1.StatefulSet, error) {
 	ss, err := o.kclient.AppsV1().StatefulSets(sk.GetNamespace()).Get(ctx, sk.GetName(), metav1.GetOptions{})
 	notFound := false
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			notFound = true
 		} else {
-			return fmt.Errorf("failed to get safekeeper statefulset: %w", err)
+			return nil, fmt.Errorf("failed to get safekeeper statefulset: %w", err)
 		}
 	}
 
-	spec, err := makeSafeKeeperStatefulSetSpec(sk, profile, storageBrokerTLSEnabled)
+	spec, err := makeSafeKeeperStatefulSetSpec(sk, profile, storageBrokerTLSEnabled, podAnnotations)
 	if err != nil {
-		return fmt.Errorf("failed to create safekeeper statefulset spec: %w", err)
+		return nil, fmt.Errorf("failed to create safekeeper statefulset spec: %w", err)
 	}
 	sset, err := makeSafeKeeperStatefulSet(sk, spec)
 	if err != nil {
-		return fmt.Errorf("failed to create safekeeper statefulset object: %w", err)
+		return nil, fmt.Errorf("failed to create safekeeper statefulset object: %w", err)
 	}
 	hash, err := k8sutils.CreateInputHash(sk.ObjectMeta, spec)
 	if err != nil {
-		return fmt.Errorf("failed to create input hash for safekeeper statefulset: %w", err)
+		return nil, fmt.Errorf("failed to create input hash for safekeeper statefulset: %w", err)
 	}
 
 	if notFound {
-		if ss.Annotations == nil {
-			ss.Annotations = make(map[string]string)
+		if sset.Annotations == nil {
+			sset.Annotations = make(map[string]string)
 		}
-		ss.Annotations[k8sutils.InputHashAnnotationKey] = hash
+		sset.Annotations[k8sutils.InputHashAnnotationKey] = hash
 
-		_, err = o.kclient.AppsV1().StatefulSets(sk.GetNamespace()).Create(ctx, ss, metav1.CreateOptions{})
+		if err := operator.AdoptOrphanedPods(ctx, o.kclient, sset); err != nil {
+			return nil, fmt.Errorf("failed to adopt orphaned safekeeper pods: %w", err)
+		}
+		created, err := o.kclient.AppsV1().StatefulSets(sk.GetNamespace()).Create(ctx, sset, metav1.CreateOptions{})
 		if err != nil {
-			return fmt.Errorf("failed to create safekeeper statefulset: %w", err)
+			return nil, fmt.Errorf("failed to create safekeeper statefulset: %w", err)
 		}
-		return nil
+		return created, nil
 	}
 
 	if ss.Annotations[k8sutils.InputHashAnnotationKey] == hash {
 		// No update needed
-		return nil
+		return ss, nil
+	}
+
+	if !equality.Semantic.DeepEqual(ss.Spec.Selector, sset.Spec.Selector) || ss.Spec.ServiceName != sset.Spec.ServiceName {
+		// The selector and service name are immutable. The StatefulSet is recreated on the next reconcile.
+		// Its pods and volume claims are orphaned, the pods are relabeled with the new selector before the new
+		// StatefulSet is created, which adopts and rolls them.
+		o.logger.Warn("safekeeper statefulset identity changed, recreating it", "name", ss.Name, "namespace", ss.Namespace)
+		propagation := metav1.DeletePropagationOrphan
+		err = o.kclient.AppsV1().StatefulSets(sk.GetNamespace()).Delete(ctx, ss.Name, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete safekeeper statefulset: %w", err)
+		}
+		return nil, nil
 	}
 
 	ss.Spec = sset.Spec
@@ -190,16 +317,16 @@ func (o *Operator) updateStatefulSet(ctx context.Context, sk *v1alpha1.SafeKeepe
 	maps.Copy(ss.Annotations, sset.Annotations)
 	ss.Annotations[k8sutils.InputHashAnnotationKey] = hash
 
-	_, err = o.kclient.AppsV1().StatefulSets(sk.GetNamespace()).Update(ctx, ss, metav1.UpdateOptions{})
+	updated, err := o.kclient.AppsV1().StatefulSets(sk.GetNamespace()).Update(ctx, ss, metav1.UpdateOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to update safekeeper statefulset: %w", err)
+		return nil, fmt.Errorf("failed to update safekeeper statefulset: %w", err)
 	}
 
-	return nil
+	return updated, nil
 }
 
 func (o *Operator) updateHeadlessService(ctx context.Context, sk *v1alpha1.SafeKeeper) error {
-	svc, err := o.kclient.CoreV1().Services(sk.GetNamespace()).Get(ctx, "safekeeper", metav1.GetOptions{})
+	svc, err := o.kclient.CoreV1().Services(sk.GetNamespace()).Get(ctx, sk.GetName(), metav1.GetOptions{})
 	notFound := false
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -39,6 +40,7 @@ const controllerName = "safekeeper-controller"
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -54,13 +56,20 @@ func (r *Operator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result
 // SetupWithManager sets up the controller with the Manager.
 func (r *Operator) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
+		// Children are owned without a controller reference
 		For(&corev1alpha1.SafeKeeper{}).
-		Owns(&appsv1.StatefulSet{}).
-		Owns(&corev1.Service{}).
+		Owns(&appsv1.StatefulSet{}, builder.MatchEveryOwner).
+		Owns(&corev1.Service{}, builder.MatchEveryOwner).
+		Owns(&corev1.Secret{}, builder.MatchEveryOwner).
 		Watches(
 			&corev1alpha1.SafeKeeperProfile{},
 			handler.EnqueueRequestsFromMapFunc(r.mapSafeKeeperProfileToSafeKeepers),
 		).
+		// The pods trust the public keys of the copied JWT keys Secret
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.mapJWTKeysSecretToSafeKeepers),
+		).
 		Named("safekeeper").
 		Complete(r)
 }
@@ -96,3 +105,27 @@ func (r *Operator) mapSafeKeeperProfileToSafeKeepers(ctx context.Context, obj cl
 
 	return requests
 }
+
+// mapJWTKeysSecretToSafeKeepers maps a JWT public keys Secret change to all SafeKeepers that reference it.
+func (r *Operator) mapJWTKeysSecretToSafeKeepers(ctx context.Context, obj client.Object) []reconcile.Request {
+	safekeepers := &corev1alpha1.SafeKeeperList{}
+	if err := r.nclient.List(ctx, safekeepers, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.logger.Error("failed to list safekeepers", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, sk := range safekeepers.Items {
+		ref := sk.Spec.JwtPublicKeySecretRef
+		if ref != nil && ref.Name == obj.GetName() && (ref.Namespace == "" || ref.Namespace == obj.GetNamespace()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      sk.Name,
+					Namespace: sk.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}
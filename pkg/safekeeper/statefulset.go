@@ -32,25 +32,58 @@ const (
 	NeonDefaultImage  = "ghcr.io/neondatabase/neon:latest"
 	TLSCertPath       = "/etc/safekeeper/certs/tls.crt"
 	TLSKeyPath        = "/etc/safekeeper/certs/tls.key"
+	CACertPath        = caCertDir + "/" + controlplane.CACertKey
+	caCertDir         = "/etc/safekeeper/ca"
+	caCertVolumeName  = "ca-cert"
 	tlsVolumeName     = "tls-certs"
-	PublicKeyPath     = "/etc/safekeeper/certs/jwt.pub"
-	JwtKeyPath        = "/etc/safekeeper/certs/jwt.txt"
+	PublicKeyPath     = "/etc/safekeeper/jwt"
+	JwtKeyPath        = tokenDir + "/" + controlplane.TokenSecretKey
 	jwtVolumeNameName = "jwt-public-key"
+	tokenVolumeName   = "token"
+	tokenDir          = "/etc/safekeeper/token"
+
+	// PgPort is the port safekeepers accept WAL on
+	PgPort = 5454
+	// HTTPPort is the port of the safekeeper HTTP API
+	HTTPPort = 7676
 )
 
-// buildSafeKeeperArgs builds the command-line arguments for the safekeeper process
-func buildSafeKeeperArgs(nodeID int32, sf *v1alpha1.SafeKeeper, opts *v1alpha1.SafeKeeperConfigOptions, storageBrokerTLSEnabled bool) []string {
-	args := []string{
-		fmt.Sprintf("--id=%d", nodeID),
+// nodeIDScript starts the safekeeper with the node ID of the pod, the ordinal in its hostname plus one.
+// The ID is stable across restarts and unique within the StatefulSet.
+const nodeIDScript = `ORDINAL="${HOSTNAME##*-}"
+if ! echo "$ORDINAL" | grep -qE '^[0-9]+$'; then
+  echo "failed to extract ordinal from hostname: $HOSTNAME" >&2
+  exit 1
+fi
+exec safekeeper --id="$((ORDINAL + 1))" "$@"`
+
+// SafeKeeperNodeID returns the node ID of the safekeeper pod with the given StatefulSet ordinal.
+// It must match nodeIDScript.
+func SafeKeeperNodeID(ordinal int32) int64 {
+	return int64(ordinal) + 1
+}
+
+// safeKeeperReplicas returns the number of safekeeper pods, MinReplicas is used as the desired count
+func safeKeeperReplicas(skp *v1alpha1.SafeKeeperProfile) int32 {
+	replicas := int32(1)
+	if skp.Spec.MinReplicas != nil {
+		replicas = int32(*skp.Spec.MinReplicas)
 	}
+	return replicas
+}
+
+// buildSafeKeeperArgs builds the command-line arguments for the safekeeper process
+// The node ID is not part of them, it is derived from the pod ordinal by the container command.
+func buildSafeKeeperArgs(sf *v1alpha1.SafeKeeper, opts *v1alpha1.SafeKeeperConfigOptions, storageBrokerTLSEnabled bool) []string {
+	args := []string{}
 
 	if opts == nil {
 		return args
 	}
 
-	args = append(args, fmt.Sprintf("--listen_pg=%s", "0.0.0.0:5454"))
-	args = append(args, fmt.Sprintf("--listen_http=%s", "0.0.0.0:7676"))
-	args = append(args, fmt.Sprintf("--advertise_pg=%s", "$(HOSTNAME).safekeeper.$(POD_NAMESPACE).svc.cluster.local:5454"))
+	args = append(args, fmt.Sprintf("--listen_pg=0.0.0.0:%d", PgPort))
+	args = append(args, fmt.Sprintf("--listen_http=0.0.0.0:%d", HTTPPort))
+	args = append(args, fmt.Sprintf("--advertise_pg=$(HOSTNAME).%s.$(POD_NAMESPACE).svc.cluster.local:%d", sf.Name, PgPort))
 
 	brokerProtocol := "http"
 	brokerPort := "50051"
@@ -102,6 +135,9 @@ func buildSafeKeeperArgs(nodeID int32, sf *v1alpha1.SafeKeeper, opts *v1alpha1.S
 
 	remoteStorageParts = append(remoteStorageParts, fmt.Sprintf("bucket_region = \"%s\"", sf.Spec.ObjectStorage.Region))
 
+	// WAL is stored next to the pageserver layers, under the prefix of the NeonCluster
+	remoteStorageParts = append(remoteStorageParts, fmt.Sprintf("prefix_in_bucket = \"%s/safekeeper/\"", sf.Spec.ObjectStorage.Prefix))
+
 	// Add bucket endpoint for local and minio providers
 	if sf.Spec.ObjectStorage.Provider == "local" || sf.Spec.ObjectStorage.Provider == "minio" {
 		if sf.Spec.ObjectStorage.Endpoint != "" {
@@ -124,10 +160,10 @@ func buildSafeKeeperArgs(nodeID int32, sf *v1alpha1.SafeKeeper, opts *v1alpha1.S
 		args = append(args, fmt.Sprintf("--ssl_cert_reload_period=%s", *opts.SslCertReloadPeriod))
 	}
 
-	if opts.UseHttpsSafekeeperApi && controlplane.GetEnableTLS() {
+	if opts.UseHttpsSafekeeperApi && controlplane.GetEnableTLS() && sf.Spec.TLSSecretRef != nil {
 		args = append(args, "--use_https_safekeeper_api=true")
 		args = append(args, "--listen-https=0.0.0.0:7676")
-		args = append(args, fmt.Sprintf("--ssl_ca_file=%s", TLSCertPath))
+		args = append(args, fmt.Sprintf("--ssl_ca_file=%s", CACertPath))
 		args = append(args, fmt.Sprintf("--ssl_cert_file=%s", TLSCertPath))
 		args = append(args, fmt.Sprintf("--ssl_key_file=%s", TLSKeyPath))
 	}
@@ -213,6 +249,10 @@ func buildSafeKeeperArgs(nodeID int32, sf *v1alpha1.SafeKeeper, opts *v1alpha1.S
 func makeSafeKeeperStatefulSet(sk *v1alpha1.SafeKeeper, spec *appsv1.StatefulSetSpec) (*appsv1.StatefulSet, error) {
 
 	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sk.Name,
+			Namespace: sk.Namespace,
+		},
 		Spec: *spec,
 	}
 
@@ -224,24 +264,20 @@ func makeSafeKeeperStatefulSet(sk *v1alpha1.SafeKeeper, spec *appsv1.StatefulSet
 	return statefulSet, nil
 }
 
-func makeSafeKeeperStatefulSetSpec(sk *v1alpha1.SafeKeeper, skp *v1alpha1.SafeKeeperProfile, storageBrokerTLSEnabled bool) (*appsv1.StatefulSetSpec, error) {
+func makeSafeKeeperStatefulSetSpec(sk *v1alpha1.SafeKeeper, skp *v1alpha1.SafeKeeperProfile, storageBrokerTLSEnabled bool, podAnnotations map[string]string) (*appsv1.StatefulSetSpec, error) {
 	cpf := skp.Spec.CommonFields
-	jwtToken := controlplane.GetJWTToken()
+	enableJWT := controlplane.GetEnableJWT()
 
 	image := NeonDefaultImage
 	if cpf.Image != nil {
 		image = *cpf.Image
 	}
 
-	// Set replicas (using MinReplicas as the desired count)
-	replicas := int32(1)
-	if skp.Spec.MinReplicas != nil {
-		replicas = int32(*skp.Spec.MinReplicas)
-	}
+	replicas := safeKeeperReplicas(skp)
 
-	// Build pod labels
+	// Build pod labels, scoped to the SafeKeeper so that several clusters can share a namespace
 	labels := map[string]string{
-		"app":       "safekeeper",
+		"app":       sk.Name,
 		"component": "safekeeper-statefulset",
 	}
 
@@ -271,10 +307,6 @@ func makeSafeKeeperStatefulSetSpec(sk *v1alpha1.SafeKeeper, skp *v1alpha1.SafeKe
 				},
 			},
 		},
-		{
-			Name:  "JWT_TOKEN",
-			Value: jwtToken,
-		},
 	}
 
 	// Add credentials environment variables for object storage
@@ -344,12 +376,7 @@ func makeSafeKeeperStatefulSetSpec(sk *v1alpha1.SafeKeeper, skp *v1alpha1.SafeKe
 		}
 	}
 
-	// Note: For StatefulSet, each pod's nodeID is derived from its ordinal
-	// (e.g., pod name "safekeeper-0" gets ID 0, "safekeeper-1" gets ID 1)
-	// We use ordinal 0 as a base for the args spec; individual pods
-	// must override the --id argument with their actual ordinal
-	// This is typically handled by a wrapper script or init container
-	args := buildSafeKeeperArgs(0, sk, &skp.Spec.SafeKeeperConfigOptions, storageBrokerTLSEnabled)
+	args := buildSafeKeeperArgs(sk, &skp.Spec.SafeKeeperConfigOptions, storageBrokerTLSEnabled)
 
 	container := corev1.Container{
 		Name:            "safekeeper",
@@ -359,10 +386,13 @@ func makeSafeKeeperStatefulSetSpec(sk *v1alpha1.SafeKeeper, skp *v1alpha1.SafeKe
 		VolumeMounts:    skp.Spec.VolumeMounts,
 		Args:            args,
 		Env:             env,
+		// $(HOSTNAME) and $(POD_NAMESPACE) in the args are expanded by the kubelet,
+		// the node ID is derived from the ordinal of the pod, see SafeKeeperNodeID
 		Command: []string{
 			"sh",
 			"-c",
-			`eval "set -- $(printf '%s\n' "$@" | sed "s|\$(HOSTNAME)|$HOSTNAME|g; s|\$(POD_NAMESPACE)|$POD_NAMESPACE|g")" && exec safekeeper "$@"`,
+			nodeIDScript,
+			"safekeeper",
 		},
 	}
 
@@ -394,20 +424,29 @@ func makeSafeKeeperStatefulSetSpec(sk *v1alpha1.SafeKeeper, skp *v1alpha1.SafeKe
 		})
 	}
 
+	// Add the CA certificate volume mount, it verifies the control plane and the other services
+	if controlplane.GetEnableTLS() {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      caCertVolumeName,
+			MountPath: caCertDir,
+			ReadOnly:  true,
+		})
+	}
+
 	// Add JWT public key secret volume mount
 	if sk.Spec.JwtPublicKeySecretRef != nil {
 		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
 			Name:      jwtVolumeNameName,
-			MountPath: "/etc/safekeeper/certs",
+			MountPath: PublicKeyPath,
 			ReadOnly:  true,
 		})
 	}
 
-	// Add JWT token temp volume mount if JWT is provided
-	if jwtToken != "" {
+	// Add the control plane token secret volume mount if JWT is enabled
+	if enableJWT {
 		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
-			Name:      "jwt-temp",
-			MountPath: "/etc/safekeeper/certs",
+			Name:      tokenVolumeName,
+			MountPath: tokenDir,
 			ReadOnly:  true,
 		})
 	}
@@ -430,15 +469,7 @@ if ! echo "$ORDINAL" | grep -qE '^[0-9]+$'; then
   exit 1
 fi
 
-echo "Pod: $POD_NAME, Ordinal: $ORDINAL" >&2
-
-# Create JWT file if JWT_TOKEN is provided
-if [ -n "$JWT_TOKEN" ]; then
-  mkdir -p /etc/safekeeper/certs
-  echo "$JWT_TOKEN" > /etc/safekeeper/certs/jwt.txt
-  chmod 600 /etc/safekeeper/certs/jwt.txt
-  echo "JWT token file created at /etc/safekeeper/certs/jwt.txt" >&2
-fi`,
+echo "Pod: $POD_NAME, Ordinal: $ORDINAL" >&2`,
 			},
 			Env: []corev1.EnvVar{
 				{
@@ -449,27 +480,19 @@ fi`,
 						},
 					},
 				},
-				{
-					Name:  "JWT_TOKEN",
-					Value: jwtToken,
-				},
-			},
-			VolumeMounts: []corev1.VolumeMount{
-				{
-					Name:      "jwt-temp",
-					MountPath: "/etc/safekeeper/certs",
-				},
 			},
 		},
 	}
 
-	// Add JWT temp volume to the volumes list
+	// Add the control plane token secret volume, the token is never part of the pod spec
 	volumes := append([]corev1.Volume{}, skp.Spec.Volumes...)
-	if jwtToken != "" {
+	if enableJWT {
 		volumes = append(volumes, corev1.Volume{
-			Name: "jwt-temp",
+			Name: tokenVolumeName,
 			VolumeSource: corev1.VolumeSource{
-				EmptyDir: &corev1.EmptyDirVolumeSource{},
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: controlplane.TokenSecretName(sk.Name),
+				},
 			},
 		})
 	}
@@ -477,6 +500,8 @@ fi`,
 	podTemplateSpec := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels: labels,
+			// The public keys are read on startup, the pods restart when they change
+			Annotations: podAnnotations,
 		},
 		Spec: corev1.PodSpec{
 			InitContainers:   initContainers,
@@ -538,6 +563,20 @@ fi`,
 		})
 	}
 
+	// Add the CA certificate volume if TLS is enabled, copied into the namespace by the NeonCluster
+	if controlplane.GetEnableTLS() {
+		optional := true
+		podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
+			Name: caCertVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: controlplane.CACertSecretName,
+					Optional:   &optional,
+				},
+			},
+		})
+	}
+
 	// Add JWT public key secret volume if JWT is enabled and secret is referenced
 	if sk.Spec.JwtPublicKeySecretRef != nil {
 		podTemplateSpec.Spec.Volumes = append(podTemplateSpec.Spec.Volumes, corev1.Volume{
@@ -555,7 +594,7 @@ fi`,
 		Selector: &metav1.LabelSelector{
 			MatchLabels: labels,
 		},
-		ServiceName:                          "safekeeper",
+		ServiceName:                          sk.Name,
 		Template:                             podTemplateSpec,
 		PersistentVolumeClaimRetentionPolicy: skp.Spec.PersistentVolumeClaimRetentionPolicy,
 	}
@@ -586,27 +625,27 @@ fi`,
 func makeSafeKeeperHeadlessService(sk *v1alpha1.SafeKeeper) *corev1.Service {
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "safekeeper",
+			Name:      sk.Name,
 			Namespace: sk.Namespace,
 			Labels: map[string]string{
-				"app":       "safekeeper",
+				"app":       sk.Name,
 				"component": "safekeeper-service",
 			},
 		},
 		Spec: corev1.ServiceSpec{
 			ClusterIP: "None", // Headless service
 			Selector: map[string]string{
-				"app": "safekeeper",
+				"app": sk.Name,
 			},
 			Ports: []corev1.ServicePort{
 				{
 					Name:     "pg",
-					Port:     5432,
+					Port:     PgPort,
 					Protocol: corev1.ProtocolTCP,
 				},
 				{
 					Name:     "http",
-					Port:     9898,
+					Port:     HTTPPort,
 					Protocol: corev1.ProtocolTCP,
 				},
 			},
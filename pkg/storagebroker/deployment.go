@@ -39,6 +39,10 @@ const (
 func makeStorageBrokerDeployment(sb *v1alpha1.StorageBroker, spec *appsv1.DeploymentSpec) (*appsv1.Deployment, error) {
 
 	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sb.Name,
+			Namespace: sb.Namespace,
+		},
 		Spec: *spec,
 	}
 
@@ -161,6 +165,10 @@ func makeStorageBrokerDeploymentSpec(sb *v1alpha1.StorageBroker, sbp *v1alpha1.S
 // makeStorageBrokerService creates a ClusterIP Service for the StorageBroker component
 func makeStorageBrokerService(sb *v1alpha1.StorageBroker) (*corev1.Service, error) {
 	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sb.Name,
+			Namespace: sb.Namespace,
+		},
 		Spec: corev1.ServiceSpec{
 			Type: corev1.ServiceTypeClusterIP,
 			Selector: map[string]string{
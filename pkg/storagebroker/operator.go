@@ -18,10 +18,12 @@ package storagebroker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
 
+	appsv1 "k8s.io/api/apps/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +33,8 @@ import (
 
 	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
 	k8sutils "github.com/stateless-pg/stateless-pg/pkg/k8s-utils"
+	"github.com/stateless-pg/stateless-pg/pkg/operator"
+	"github.com/stateless-pg/stateless-pg/pkg/profile"
 )
 
 // Operator manages lifecycle for StorageBroker resources.
@@ -80,49 +84,106 @@ func (o *Operator) sync(ctx context.Context, name, namespace string) error {
 	logger := o.logger.With("key", key)
 	logger.Info("syncing storagebroker")
 
-	profile := &v1alpha1.StorageBrokerProfile{}
+	dep, profileHash, syncErr := o.reconcile(ctx, sb)
+
+	// Status is written even when the reconcile failed, Degraded reports the failure
+	if err := o.updateStatus(ctx, sb, dep, profileHash, syncErr); err != nil {
+		if syncErr != nil {
+			logger.Error("failed to update storagebroker status", "error", err)
+			return syncErr
+		}
+		return err
+	}
+
+	if errors.Is(syncErr, operator.ErrProfileRolloutPending) {
+		// Requeued by the profile watch once the rollout admits it
+		return nil
+	}
+	return syncErr
+}
+
+// reconcile creates or updates the children of the StorageBroker.
+// It returns the Deployment and the hash of the applied profile.
+func (o *Operator) reconcile(ctx context.Context, sb *v1alpha1.StorageBroker) (*appsv1.Deployment, string, error) {
+	sbp := &v1alpha1.StorageBrokerProfile{}
 	if err := o.nclient.Get(ctx, client.ObjectKey{
 		Name:      sb.Spec.ProfileRef.Name,
 		Namespace: sb.Spec.ProfileRef.Namespace,
-	}, profile); err != nil {
-		return fmt.Errorf("failed to get storagebroker profile : %w", err)
+	}, sbp); err != nil {
+		return nil, "", fmt.Errorf("failed to get storagebroker profile : %w", err)
+	}
+
+	// Merge the base profiles the profile inherits from
+	sbp, err := profile.ResolveStorageBrokerProfile(ctx, o.nclient, sbp)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve storagebroker profile: %w", err)
+	}
+
+	profileHash, err := profile.Hash(sbp)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create input hash for storagebroker profile: %w", err)
 	}
 
-	profile = profile.DeepCopy()
+	// A change of a profile with a rollout policy waits until the rollout admits this StorageBroker
+	if !profile.RolloutAdmitted(sbp, sb, sb.Status.ProfileHash, profileHash) {
+		dep, err := o.kclient.AppsV1().Deployments(sb.GetNamespace()).Get(ctx, sb.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, sb.Status.ProfileHash, fmt.Errorf("failed to get storagebroker deployment: %w", err)
+		}
+		return dep, sb.Status.ProfileHash, operator.ErrProfileRolloutPending
+	}
 
-	if err := o.updateDeployment(ctx, sb, profile); err != nil {
-		return fmt.Errorf("failed to reconcile storagebroker deployment: %w", err)
+	dep, err := o.updateDeployment(ctx, sb, sbp)
+	if err != nil {
+		return nil, profileHash, fmt.Errorf("failed to reconcile storagebroker deployment: %w", err)
 	}
 
 	if err := o.updateService(ctx, sb); err != nil {
-		return fmt.Errorf("failed to reconcile storagebroker service: %w", err)
+		return dep, profileHash, fmt.Errorf("failed to reconcile storagebroker service: %w", err)
+	}
+
+	return dep, profileHash, nil
+}
+
+// updateStatus persists the workload status and conditions of the StorageBroker
+func (o *Operator) updateStatus(ctx context.Context, sb *v1alpha1.StorageBroker, dep *appsv1.Deployment, profileHash string, syncErr error) error {
+	if profileHash == "" {
+		// The profile could not be read, keep reporting the last applied one
+		profileHash = sb.Status.ProfileHash
 	}
 
+	sb.Status.WorkloadStatus = operator.DeploymentStatus(dep, sb.Generation, profileHash)
+	operator.SetWorkloadConditions(&sb.Status.Conditions, sb.Generation, &sb.Status.WorkloadStatus,
+		dep != nil, operator.DeploymentRolledOut(dep), syncErr)
+
+	if err := o.nclient.Status().Update(ctx, sb); err != nil {
+		return fmt.Errorf("failed to update storagebroker status: %w", err)
+	}
 	return nil
 }
 
-func (o *Operator) updateDeployment(ctx context.Context, sb *v1alpha1.StorageBroker, profile *v1alpha1.StorageBrokerProfile) error {
+func (o *Operator) updateDeployment(ctx context.Context, sb *v1alpha1.StorageBroker, profile *v1alpha1.StorageBrokerProfile) (*appsv1.Deployment, error) {
 	dep, err := o.kclient.AppsV1().Deployments(sb.GetNamespace()).Get(ctx, sb.GetName(), metav1.GetOptions{})
 	notFound := false
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			notFound = true
 		} else {
-			return fmt.Errorf("failed to get storagebroker deployment: %w", err)
+			return nil, fmt.Errorf("failed to get storagebroker deployment: %w", err)
 		}
 	}
 
 	spec, err := makeStorageBrokerDeploymentSpec(sb, profile)
 	if err != nil {
-		return fmt.Errorf("failed to create storagebroker deployment spec: %w", err)
+		return nil, fmt.Errorf("failed to create storagebroker deployment spec: %w", err)
 	}
 	deployment, err := makeStorageBrokerDeployment(sb, spec)
 	if err != nil {
-		return fmt.Errorf("failed to create storagebroker deployment object: %w", err)
+		return nil, fmt.Errorf("failed to create storagebroker deployment object: %w", err)
 	}
 	hash, err := k8sutils.CreateInputHash(sb.ObjectMeta, spec)
 	if err != nil {
-		return fmt.Errorf("failed to create input hash for storagebroker deployment: %w", err)
+		return nil, fmt.Errorf("failed to create input hash for storagebroker deployment: %w", err)
 	}
 
 	if notFound {
@@ -131,16 +192,16 @@ func (o *Operator) updateDeployment(ctx context.Context, sb *v1alpha1.StorageBro
 		}
 		deployment.Annotations[k8sutils.InputHashAnnotationKey] = hash
 
-		_, err = o.kclient.AppsV1().Deployments(sb.GetNamespace()).Create(ctx, deployment, metav1.CreateOptions{})
+		created, err := o.kclient.AppsV1().Deployments(sb.GetNamespace()).Create(ctx, deployment, metav1.CreateOptions{})
 		if err != nil {
-			return fmt.Errorf("failed to create storagebroker deployment: %w", err)
+			return nil, fmt.Errorf("failed to create storagebroker deployment: %w", err)
 		}
-		return nil
+		return created, nil
 	}
 
 	if dep.Annotations[k8sutils.InputHashAnnotationKey] == hash {
 		// No update needed
-		return nil
+		return dep, nil
 	}
 
 	dep.Spec = deployment.Spec
@@ -151,12 +212,12 @@ func (o *Operator) updateDeployment(ctx context.Context, sb *v1alpha1.StorageBro
 	maps.Copy(dep.Annotations, deployment.Annotations)
 	dep.Annotations[k8sutils.InputHashAnnotationKey] = hash
 
-	_, err = o.kclient.AppsV1().Deployments(sb.GetNamespace()).Update(ctx, dep, metav1.UpdateOptions{})
+	updated, err := o.kclient.AppsV1().Deployments(sb.GetNamespace()).Update(ctx, dep, metav1.UpdateOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to update storagebroker deployment: %w", err)
+		return nil, fmt.Errorf("failed to update storagebroker deployment: %w", err)
 	}
 
-	return nil
+	return updated, nil
 }
 
 func (o *Operator) updateService(ctx context.Context, sb *v1alpha1.StorageBroker) error {
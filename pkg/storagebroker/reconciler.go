@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -53,9 +54,10 @@ func (r *Operator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result
 // SetupWithManager sets up the controller with the Manager.
 func (r *Operator) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
+		// Children are owned without a controller reference
 		For(&corev1alpha1.StorageBroker{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&corev1.Service{}).
+		Owns(&appsv1.Deployment{}, builder.MatchEveryOwner).
+		Owns(&corev1.Service{}, builder.MatchEveryOwner).
 		Watches(
 			&corev1alpha1.StorageBrokerProfile{},
 			handler.EnqueueRequestsFromMapFunc(r.mapStorageBrokerProfileToStorageBrokers),
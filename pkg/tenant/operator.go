@@ -0,0 +1,394 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
+	pageserverapi "github.com/stateless-pg/stateless-pg/pkg/pageserver-api"
+)
+
+// tenantFinalizer makes sure the tenant is removed from the pageserver before the resource is deleted
+const tenantFinalizer = "neon.io/tenant"
+
+// Operator manages lifecycle for Tenant resources.
+type Operator struct {
+	nclient     client.Client
+	kclient     kubernetes.Interface
+	scheme      *runtime.Scheme
+	logger      *slog.Logger
+	generations *controlplane.GenerationStore
+	notifier    *controlplane.ComputeNotifier
+}
+
+// New creates a new Tenant Operator.
+func New(nclient client.Client, scheme *runtime.Scheme, logger *slog.Logger, config *rest.Config) (*Operator, error) {
+	logger = logger.With("component", controllerName)
+
+	// Create kubernetes clientset for direct client-go operations
+	kclient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	return &Operator{
+		logger:      logger,
+		nclient:     nclient,
+		kclient:     kclient,
+		scheme:      scheme,
+		generations: controlplane.NewGenerationStore(kclient),
+		notifier:    controlplane.NewComputeNotifier(nclient, kclient, logger),
+	}, nil
+}
+
+// sync reconciles the Tenant resource state with the desired state.
+func (o *Operator) sync(ctx context.Context, name, namespace string) error {
+
+	tn := &v1alpha1.Tenant{}
+	if err := o.nclient.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, tn); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	tn = tn.DeepCopy()
+
+	key := fmt.Sprintf("%s/%s", namespace, name)
+
+	logger := o.logger.With("key", key)
+	logger.Info("syncing tenant")
+
+	if !tn.DeletionTimestamp.IsZero() {
+		return o.delete(ctx, tn, logger)
+	}
+
+	if !controllerutil.ContainsFinalizer(tn, tenantFinalizer) || tn.Spec.TenantID == "" {
+		// Persist the finalizer and the tenant ID before anything is created on a pageserver,
+		// the update triggers a new reconcile
+		controllerutil.AddFinalizer(tn, tenantFinalizer)
+		if tn.Spec.TenantID == "" {
+			id, err := generateTenantID()
+			if err != nil {
+				return err
+			}
+			tn.Spec.TenantID = id
+		}
+		if err := o.nclient.Update(ctx, tn); err != nil {
+			return fmt.Errorf("failed to update tenant: %w", err)
+		}
+		return nil
+	}
+
+	terminating, err := o.clusterTerminating(ctx, tn)
+	if err != nil {
+		return err
+	}
+	if terminating {
+		// The NeonCluster teardown detaches the tenant, attaching it again would race with it
+		meta.SetStatusCondition(&tn.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TenantConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ClusterTerminating",
+			Message:            fmt.Sprintf("neoncluster %s is being deleted", tn.Spec.NeonClusterRef.Name),
+			ObservedGeneration: tn.Generation,
+		})
+		return o.updateStatus(ctx, tn)
+	}
+
+	moved, err := o.attach(ctx, tn, logger)
+	if err != nil {
+		meta.SetStatusCondition(&tn.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TenantConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "AttachFailed",
+			Message:            err.Error(),
+			ObservedGeneration: tn.Generation,
+		})
+		if statusErr := o.updateStatus(ctx, tn); statusErr != nil {
+			logger.Error("failed to update tenant status", "error", statusErr)
+		}
+		return err
+	}
+
+	meta.SetStatusCondition(&tn.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.TenantConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Attached",
+		Message:            fmt.Sprintf("tenant is attached to %s", tn.Status.PageServerPod),
+		ObservedGeneration: tn.Generation,
+	})
+
+	if err := o.updateStatus(ctx, tn); err != nil {
+		return err
+	}
+
+	if moved {
+		// Running computes keep reading from the old pageserver until they get the new attachment.
+		// Failures are retried by the compute controller, which compares the configured connstring.
+		if err := o.notifier.NotifyTenant(ctx, tn); err != nil {
+			logger.Warn("failed to notify computes of the new attachment", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// attach attaches the tenant to a pageserver pod of its NeonCluster and applies its configuration.
+// The tenant stays on its current pod as long as that pod exists.
+// It reports whether a previously attached tenant moved to a new generation.
+func (o *Operator) attach(ctx context.Context, tn *v1alpha1.Tenant, logger *slog.Logger) (bool, error) {
+	ps, err := o.getPageServer(ctx, tn)
+	if err != nil {
+		return false, err
+	}
+
+	pod, err := o.selectPod(ctx, tn, ps)
+	if err != nil {
+		return false, err
+	}
+
+	psClient := o.newPageServerClient(ps, pod)
+
+	status, err := psClient.Status(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get status of pageserver %s: %w", pod, err)
+	}
+
+	cluster := types.NamespacedName{
+		Name:      tn.Spec.NeonClusterRef.Name,
+		Namespace: tn.Namespace,
+	}
+
+	current, err := o.generations.Get(ctx, cluster, tn.Spec.TenantID)
+	if err != nil {
+		return false, err
+	}
+
+	var gen uint32
+	moved := false
+	if current != nil && current.NodeID == status.ID {
+		gen = current.Generation
+	} else {
+		// A new generation fences off any previous attachment of the tenant
+		gen, err = o.generations.Attach(ctx, cluster, tn.Spec.TenantID, status.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to attach tenant generation: %w", err)
+		}
+		logger.Info("attaching tenant", "pod", pod, "nodeID", status.ID, "generation", gen)
+		moved = current != nil || tn.Status.PageServerPod != ""
+	}
+
+	if err := psClient.LocationConfig(ctx, tn.Spec.TenantID, &pageserverapi.LocationConfig{
+		Mode:            pageserverapi.LocationConfigModeAttachedSingle,
+		Generation:      &gen,
+		ShardStripeSize: pageserverapi.DefaultStripeSize,
+		TenantConf:      makeTenantConf(&tn.Spec.Config),
+	}); err != nil {
+		return false, fmt.Errorf("failed to configure tenant on pageserver %s: %w", pod, err)
+	}
+
+	previous := tn.Status.PageServerPod
+	if previous != "" && previous != pod {
+		// The old pod is stale after the new attachment, failing to detach only leaves it idle
+		err := o.newPageServerClient(ps, previous).LocationConfig(ctx, tn.Spec.TenantID, &pageserverapi.LocationConfig{
+			Mode: pageserverapi.LocationConfigModeDetached,
+		})
+		if err != nil && !errors.Is(err, pageserverapi.ErrNotFound) {
+			logger.Warn("failed to detach tenant from previous pageserver", "pod", previous, "error", err)
+		}
+	}
+
+	tn.Status.ObservedGeneration = tn.Generation
+	tn.Status.TenantID = tn.Spec.TenantID
+	tn.Status.PageServerPod = pod
+	tn.Status.PageServerNodeID = int64(status.ID)
+	tn.Status.AttachGeneration = int64(gen)
+
+	return moved, nil
+}
+
+// delete removes the tenant from its pageserver and releases the finalizer
+func (o *Operator) delete(ctx context.Context, tn *v1alpha1.Tenant, logger *slog.Logger) error {
+	if !controllerutil.ContainsFinalizer(tn, tenantFinalizer) {
+		return nil
+	}
+
+	if tn.Status.PageServerPod != "" {
+		ps, err := o.getPageServer(ctx, tn)
+		switch {
+		case apierrors.IsNotFound(err):
+			// The cluster is gone together with its pageservers, nothing left to clean up
+			logger.Info("pageserver not found, skipping tenant deletion")
+		case err != nil:
+			return err
+		default:
+			err := o.newPageServerClient(ps, tn.Status.PageServerPod).DeleteTenant(ctx, tn.Spec.TenantID)
+			if err != nil && !errors.Is(err, pageserverapi.ErrNotFound) {
+				return fmt.Errorf("failed to delete tenant from pageserver %s: %w", tn.Status.PageServerPod, err)
+			}
+			logger.Info("deleted tenant", "pod", tn.Status.PageServerPod)
+		}
+	}
+
+	if tn.Spec.TenantID != "" {
+		cluster := types.NamespacedName{
+			Name:      tn.Spec.NeonClusterRef.Name,
+			Namespace: tn.Namespace,
+		}
+		if err := o.generations.Detach(ctx, cluster, tn.Spec.TenantID); err != nil {
+			return fmt.Errorf("failed to detach tenant generation: %w", err)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(tn, tenantFinalizer)
+	if err := o.nclient.Update(ctx, tn); err != nil {
+		return fmt.Errorf("failed to remove tenant finalizer: %w", err)
+	}
+
+	return nil
+}
+
+// clusterTerminating reports whether the NeonCluster referenced by the tenant is being deleted
+func (o *Operator) clusterTerminating(ctx context.Context, tn *v1alpha1.Tenant) (bool, error) {
+	nc := &v1alpha1.NeonCluster{}
+	if err := o.nclient.Get(ctx, client.ObjectKey{
+		Name:      tn.Spec.NeonClusterRef.Name,
+		Namespace: tn.Namespace,
+	}, nc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get neoncluster %s: %w", tn.Spec.NeonClusterRef.Name, err)
+	}
+
+	return !nc.DeletionTimestamp.IsZero(), nil
+}
+
+// getPageServer returns the PageServer of the NeonCluster referenced by the tenant
+func (o *Operator) getPageServer(ctx context.Context, tn *v1alpha1.Tenant) (*v1alpha1.PageServer, error) {
+	ps := &v1alpha1.PageServer{}
+	if err := o.nclient.Get(ctx, client.ObjectKey{
+		Name:      tn.Spec.NeonClusterRef.Name + "-pageserver",
+		Namespace: tn.Namespace,
+	}, ps); err != nil {
+		return nil, fmt.Errorf("failed to get pageserver of neoncluster %s: %w", tn.Spec.NeonClusterRef.Name, err)
+	}
+
+	return ps, nil
+}
+
+// selectPod returns the pageserver pod the tenant should be attached to.
+// The current pod is kept while it exists, otherwise a pod is picked from the tenant ID
+// so that tenants are spread over the pageservers.
+func (o *Operator) selectPod(ctx context.Context, tn *v1alpha1.Tenant, ps *v1alpha1.PageServer) (string, error) {
+	if tn.Status.PageServerPod != "" {
+		_, err := o.kclient.CoreV1().Pods(tn.Namespace).Get(ctx, tn.Status.PageServerPod, metav1.GetOptions{})
+		if err == nil {
+			return tn.Status.PageServerPod, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get pageserver pod: %w", err)
+		}
+	}
+
+	ss, err := o.kclient.AppsV1().StatefulSets(ps.Namespace).Get(ctx, ps.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pageserver statefulset: %w", err)
+	}
+
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	if replicas < 1 {
+		return "", fmt.Errorf("pageserver statefulset %s has no replicas", ss.Name)
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tn.Spec.TenantID))
+
+	return fmt.Sprintf("%s-%d", ss.Name, h.Sum32()%uint32(replicas)), nil
+}
+
+// newPageServerClient returns a management API client for a pod of the pageserver,
+// addressed through the headless service of the pageserver
+func (o *Operator) newPageServerClient(ps *v1alpha1.PageServer, pod string) *pageserverapi.Client {
+	return pageserverapi.NewClient(pageserverapi.PodBaseURL(pod, ps.Name, ps.Namespace), controlplane.GetToken(controlplane.ScopePageServerAPI))
+}
+
+// updateStatus persists the tenant status
+func (o *Operator) updateStatus(ctx context.Context, tn *v1alpha1.Tenant) error {
+	if err := o.nclient.Status().Update(ctx, tn); err != nil {
+		return fmt.Errorf("failed to update tenant status: %w", err)
+	}
+	return nil
+}
+
+// generateTenantID returns a random 32 character hex tenant ID
+func generateTenantID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate tenant id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// makeTenantConf converts the tenant configuration to the pageserver tenant_conf format
+func makeTenantConf(cfg *v1alpha1.TenantConfigSpec) map[string]interface{} {
+	conf := map[string]interface{}{}
+
+	setIfNotNil(conf, "checkpoint_distance", cfg.CheckpointDistance)
+	setIfNotNil(conf, "checkpoint_timeout", cfg.CheckpointTimeout)
+	setIfNotNil(conf, "compaction_period", cfg.CompactionPeriod)
+	setIfNotNil(conf, "gc_horizon", cfg.GCHorizon)
+	setIfNotNil(conf, "gc_period", cfg.GCPeriod)
+	setIfNotNil(conf, "pitr_interval", cfg.PITRInterval)
+	setIfNotNil(conf, "image_creation_threshold", cfg.ImageCreationThreshold)
+	setIfNotNil(conf, "walreceiver_connect_timeout", cfg.WalReceiverConnectTimeout)
+	setIfNotNil(conf, "lagging_wal_timeout", cfg.LaggingWalTimeout)
+	setIfNotNil(conf, "max_lsn_wal_lag", cfg.MaxLsnWalLag)
+
+	return conf
+}
+
+func setIfNotNil[T any](conf map[string]interface{}, key string, value *T) {
+	if value != nil {
+		conf[key] = *value
+	}
+}
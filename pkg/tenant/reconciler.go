@@ -0,0 +1,95 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+const controllerName = "tenant-controller"
+
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=tenants,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=tenants/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=tenants/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=pageservers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=neonclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+func (r *Operator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if err := r.sync(ctx, req.Name, req.Namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Operator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1alpha1.Tenant{}).
+		Watches(
+			&corev1alpha1.PageServer{},
+			handler.EnqueueRequestsFromMapFunc(r.mapPageServerToTenants),
+		).
+		Named("tenant").
+		Complete(r)
+}
+
+// mapPageServerToTenants maps a PageServer change to all Tenants of its NeonCluster.
+func (r *Operator) mapPageServerToTenants(ctx context.Context, obj client.Object) []reconcile.Request {
+	ps, ok := obj.(*corev1alpha1.PageServer)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	neonClusterName := ps.Labels["neoncluster"]
+	if neonClusterName == "" {
+		return []reconcile.Request{}
+	}
+
+	tenants := &corev1alpha1.TenantList{}
+	if err := r.nclient.List(ctx, tenants, client.InNamespace(ps.Namespace)); err != nil {
+		r.logger.Error("failed to list tenants", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, tn := range tenants.Items {
+		if tn.Spec.NeonClusterRef.Name == neonClusterName {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      tn.Name,
+					Namespace: tn.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}
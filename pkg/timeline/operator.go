@@ -0,0 +1,333 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeline
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+	controlplane "github.com/stateless-pg/stateless-pg/pkg/control-plane"
+	pageserverapi "github.com/stateless-pg/stateless-pg/pkg/pageserver-api"
+)
+
+const (
+	// timelineFinalizer makes sure the timeline is removed from the pageserver before the resource is deleted
+	timelineFinalizer = "neon.io/timeline"
+	// statusRefreshInterval is how often lastRecordLsn and the logical size are refreshed
+	statusRefreshInterval = 30 * time.Second
+)
+
+var (
+	// errNotReady is returned when a dependency of the timeline is not ready yet.
+	// The timeline is reconciled again when the dependency changes.
+	errNotReady = errors.New("not ready")
+	// errTenantGone is returned when the tenant of the timeline is deleted or being deleted
+	errTenantGone = fmt.Errorf("tenant is gone: %w", errNotReady)
+)
+
+// Operator manages lifecycle for Timeline resources.
+type Operator struct {
+	nclient client.Client
+	kclient kubernetes.Interface
+	scheme  *runtime.Scheme
+	logger  *slog.Logger
+}
+
+// New creates a new Timeline Operator.
+func New(nclient client.Client, scheme *runtime.Scheme, logger *slog.Logger, config *rest.Config) (*Operator, error) {
+	logger = logger.With("component", controllerName)
+
+	// Create kubernetes clientset for direct client-go operations
+	kclient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	return &Operator{
+		logger:  logger,
+		nclient: nclient,
+		kclient: kclient,
+		scheme:  scheme,
+	}, nil
+}
+
+// sync reconciles the Timeline resource state with the desired state.
+// It returns whether the timeline exists on the pageserver, in which case its status is refreshed periodically.
+func (o *Operator) sync(ctx context.Context, name, namespace string) (bool, error) {
+
+	tl := &v1alpha1.Timeline{}
+	if err := o.nclient.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, tl); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	tl = tl.DeepCopy()
+
+	key := fmt.Sprintf("%s/%s", namespace, name)
+
+	logger := o.logger.With("key", key)
+	logger.Info("syncing timeline")
+
+	if !tl.DeletionTimestamp.IsZero() {
+		return false, o.delete(ctx, tl, logger)
+	}
+
+	if !controllerutil.ContainsFinalizer(tl, timelineFinalizer) || tl.Spec.TimelineID == "" {
+		// Persist the finalizer and the timeline ID before anything is created on a pageserver,
+		// the update triggers a new reconcile
+		controllerutil.AddFinalizer(tl, timelineFinalizer)
+		if tl.Spec.TimelineID == "" {
+			id, err := generateTimelineID()
+			if err != nil {
+				return false, err
+			}
+			tl.Spec.TimelineID = id
+		}
+		if err := o.nclient.Update(ctx, tl); err != nil {
+			return false, fmt.Errorf("failed to update timeline: %w", err)
+		}
+		return false, nil
+	}
+
+	err := o.ensureTimeline(ctx, tl, logger)
+	if err != nil {
+		reason := "CreateFailed"
+		if errors.Is(err, errNotReady) {
+			reason = "Pending"
+		}
+		meta.SetStatusCondition(&tl.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.TimelineConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            err.Error(),
+			ObservedGeneration: tl.Generation,
+		})
+		if statusErr := o.updateStatus(ctx, tl); statusErr != nil {
+			logger.Error("failed to update timeline status", "error", statusErr)
+		}
+		if errors.Is(err, errNotReady) {
+			logger.Info("waiting for timeline dependencies", "reason", err)
+			return false, nil
+		}
+		return false, err
+	}
+
+	meta.SetStatusCondition(&tl.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.TimelineConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Created",
+		Message:            "timeline exists on the pageserver",
+		ObservedGeneration: tl.Generation,
+	})
+
+	return true, o.updateStatus(ctx, tl)
+}
+
+// ensureTimeline creates the timeline on the pageserver the tenant is attached to,
+// and records the pageserver view of the timeline in status
+func (o *Operator) ensureTimeline(ctx context.Context, tl *v1alpha1.Timeline, logger *slog.Logger) error {
+	tn, psClient, err := o.getTenant(ctx, tl)
+	if err != nil {
+		return err
+	}
+
+	info, err := psClient.GetTimeline(ctx, tn.Status.TenantID, tl.Spec.TimelineID)
+	if errors.Is(err, pageserverapi.ErrNotFound) {
+		req, reqErr := o.makeTimelineCreateRequest(ctx, tl, tn, psClient)
+		if reqErr != nil {
+			return reqErr
+		}
+
+		logger.Info("creating timeline", "tenantID", tn.Status.TenantID, "timelineID", tl.Spec.TimelineID)
+		info, err = psClient.CreateTimeline(ctx, tn.Status.TenantID, req)
+		if err != nil {
+			return fmt.Errorf("failed to create timeline: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get timeline: %w", err)
+	}
+
+	tl.Status.ObservedGeneration = tl.Generation
+	tl.Status.TenantID = tn.Status.TenantID
+	tl.Status.TimelineID = info.TimelineID
+	tl.Status.AncestorTimelineID = ""
+	if info.AncestorTimelineID != nil {
+		tl.Status.AncestorTimelineID = *info.AncestorTimelineID
+	}
+	tl.Status.AncestorLSN = ""
+	if info.AncestorLSN != nil {
+		tl.Status.AncestorLSN = *info.AncestorLSN
+	}
+	tl.Status.PGVersion = int32(info.PGVersion)
+	tl.Status.LastRecordLSN = info.LastRecordLSN
+	tl.Status.CurrentLogicalSize = int64(info.CurrentLogicalSize)
+
+	return nil
+}
+
+// makeTimelineCreateRequest resolves the ancestor of the timeline into a create request.
+// ancestorTimestamp is resolved into an LSN on the ancestor timeline.
+func (o *Operator) makeTimelineCreateRequest(ctx context.Context, tl *v1alpha1.Timeline, tn *v1alpha1.Tenant, psClient *pageserverapi.Client) (*pageserverapi.TimelineCreateRequest, error) {
+	req := &pageserverapi.TimelineCreateRequest{
+		NewTimelineID: tl.Spec.TimelineID,
+	}
+
+	if tl.Spec.AncestorTimelineRef == nil {
+		if tl.Spec.PGVersion != nil {
+			version := uint32(*tl.Spec.PGVersion)
+			req.PGVersion = &version
+		}
+		return req, nil
+	}
+
+	ancestor := &v1alpha1.Timeline{}
+	if err := o.nclient.Get(ctx, client.ObjectKey{
+		Name:      tl.Spec.AncestorTimelineRef.Name,
+		Namespace: tl.Namespace,
+	}, ancestor); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("ancestor timeline %s: %w", tl.Spec.AncestorTimelineRef.Name, errNotReady)
+		}
+		return nil, fmt.Errorf("failed to get ancestor timeline: %w", err)
+	}
+
+	if ancestor.Spec.TenantRef.Name != tl.Spec.TenantRef.Name {
+		return nil, fmt.Errorf("ancestor timeline %s belongs to tenant %s, not %s",
+			ancestor.Name, ancestor.Spec.TenantRef.Name, tl.Spec.TenantRef.Name)
+	}
+
+	if ancestor.Status.TimelineID == "" {
+		return nil, fmt.Errorf("ancestor timeline %s is not created yet: %w", ancestor.Name, errNotReady)
+	}
+	req.AncestorTimelineID = &ancestor.Status.TimelineID
+
+	switch {
+	case tl.Spec.AncestorLSN != nil:
+		req.AncestorStartLSN = tl.Spec.AncestorLSN
+	case tl.Spec.AncestorTimestamp != nil:
+		resp, err := psClient.GetLSNByTimestamp(ctx, tn.Status.TenantID, ancestor.Status.TimelineID, tl.Spec.AncestorTimestamp.Time)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve ancestor timestamp: %w", err)
+		}
+		// future means the timestamp is after the last record, branching at that LSN is still correct
+		if resp.Kind != "present" && resp.Kind != "future" {
+			return nil, fmt.Errorf("ancestor timeline %s has no data at %s (%s)",
+				ancestor.Name, tl.Spec.AncestorTimestamp.UTC().Format(time.RFC3339), resp.Kind)
+		}
+		req.AncestorStartLSN = &resp.LSN
+	}
+
+	return req, nil
+}
+
+// delete removes the timeline from the pageserver and releases the finalizer
+func (o *Operator) delete(ctx context.Context, tl *v1alpha1.Timeline, logger *slog.Logger) error {
+	if !controllerutil.ContainsFinalizer(tl, timelineFinalizer) {
+		return nil
+	}
+
+	if tl.Status.TimelineID != "" {
+		tn, psClient, err := o.getTenant(ctx, tl)
+		switch {
+		case errors.Is(err, errTenantGone):
+			// Timelines are deleted together with their tenant
+			logger.Info("tenant not available, skipping timeline deletion", "reason", err)
+		case err != nil:
+			return err
+		default:
+			err := psClient.DeleteTimeline(ctx, tn.Status.TenantID, tl.Status.TimelineID)
+			if err != nil && !errors.Is(err, pageserverapi.ErrNotFound) {
+				return fmt.Errorf("failed to delete timeline: %w", err)
+			}
+			logger.Info("deleted timeline", "tenantID", tn.Status.TenantID, "timelineID", tl.Status.TimelineID)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(tl, timelineFinalizer)
+	if err := o.nclient.Update(ctx, tl); err != nil {
+		return fmt.Errorf("failed to remove timeline finalizer: %w", err)
+	}
+
+	return nil
+}
+
+// getTenant returns the tenant of the timeline and a client for the pageserver it is attached to
+func (o *Operator) getTenant(ctx context.Context, tl *v1alpha1.Timeline) (*v1alpha1.Tenant, *pageserverapi.Client, error) {
+	tn := &v1alpha1.Tenant{}
+	if err := o.nclient.Get(ctx, client.ObjectKey{
+		Name:      tl.Spec.TenantRef.Name,
+		Namespace: tl.Namespace,
+	}, tn); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("tenant %s: %w", tl.Spec.TenantRef.Name, errTenantGone)
+		}
+		return nil, nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	if !tn.DeletionTimestamp.IsZero() {
+		return nil, nil, fmt.Errorf("tenant %s is being deleted: %w", tn.Name, errTenantGone)
+	}
+
+	if !meta.IsStatusConditionTrue(tn.Status.Conditions, v1alpha1.TenantConditionReady) || tn.Status.PageServerPod == "" {
+		return nil, nil, fmt.Errorf("tenant %s is not attached: %w", tn.Name, errNotReady)
+	}
+
+	service := tn.Spec.NeonClusterRef.Name + "-pageserver"
+	psClient := pageserverapi.NewClient(
+		pageserverapi.PodBaseURL(tn.Status.PageServerPod, service, tn.Namespace),
+		controlplane.GetToken(controlplane.ScopePageServerAPI),
+	)
+
+	return tn, psClient, nil
+}
+
+// updateStatus persists the timeline status
+func (o *Operator) updateStatus(ctx context.Context, tl *v1alpha1.Timeline) error {
+	if err := o.nclient.Status().Update(ctx, tl); err != nil {
+		return fmt.Errorf("failed to update timeline status: %w", err)
+	}
+	return nil
+}
+
+// generateTimelineID returns a random 32 character hex timeline ID
+func generateTimelineID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate timeline id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
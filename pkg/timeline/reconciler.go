@@ -0,0 +1,116 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timeline
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/stateless-pg/stateless-pg/pkg/api/v1alpha1"
+)
+
+const controllerName = "timeline-controller"
+
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=timelines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=timelines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=timelines/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core.stateless-pg.io,resources=tenants,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+func (r *Operator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	exists, err := r.sync(ctx, req.Name, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if exists {
+		// lastRecordLsn and the logical size change without any Kubernetes event
+		return ctrl.Result{RequeueAfter: statusRefreshInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Operator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1alpha1.Timeline{}).
+		Watches(
+			&corev1alpha1.Tenant{},
+			handler.EnqueueRequestsFromMapFunc(r.mapTenantToTimelines),
+		).
+		Watches(
+			&corev1alpha1.Timeline{},
+			handler.EnqueueRequestsFromMapFunc(r.mapTimelineToChildTimelines),
+		).
+		Named("timeline").
+		Complete(r)
+}
+
+// mapTenantToTimelines maps a Tenant change to all Timelines of the tenant.
+func (r *Operator) mapTenantToTimelines(ctx context.Context, obj client.Object) []reconcile.Request {
+	tn, ok := obj.(*corev1alpha1.Tenant)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	return r.listTimelines(ctx, tn.Namespace, func(tl *corev1alpha1.Timeline) bool {
+		return tl.Spec.TenantRef.Name == tn.Name
+	})
+}
+
+// mapTimelineToChildTimelines maps a Timeline change to all Timelines branched from it.
+func (r *Operator) mapTimelineToChildTimelines(ctx context.Context, obj client.Object) []reconcile.Request {
+	ancestor, ok := obj.(*corev1alpha1.Timeline)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	return r.listTimelines(ctx, ancestor.Namespace, func(tl *corev1alpha1.Timeline) bool {
+		return tl.Spec.AncestorTimelineRef != nil && tl.Spec.AncestorTimelineRef.Name == ancestor.Name
+	})
+}
+
+// listTimelines returns a request for every Timeline in the namespace matching the filter.
+func (r *Operator) listTimelines(ctx context.Context, namespace string, match func(*corev1alpha1.Timeline) bool) []reconcile.Request {
+	timelines := &corev1alpha1.TimelineList{}
+	if err := r.nclient.List(ctx, timelines, client.InNamespace(namespace)); err != nil {
+		r.logger.Error("failed to list timelines", "error", err)
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for i := range timelines.Items {
+		tl := &timelines.Items[i]
+		if match(tl) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      tl.Name,
+					Namespace: tl.Namespace,
+				},
+			})
+		}
+	}
+
+	return requests
+}